@@ -42,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/accounts/abi"
 	"github.com/gattaca-com/oracle-evm/accounts/abi/bind"
 	"github.com/gattaca-com/oracle-evm/consensus/dummy"
@@ -55,6 +56,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/ethdb"
 	"github.com/gattaca-com/oracle-evm/interfaces"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/rpc"
 )
 
@@ -82,6 +84,7 @@ var (
 	errBlockNumberUnsupported  = errors.New("simulatedBackend cannot access blocks other than the latest block")
 	errBlockDoesNotExist       = errors.New("block does not exist in blockchain")
 	errTransactionDoesNotExist = errors.New("transaction does not exist")
+	errPriceOracleDisabled     = errors.New("the price oracle is not enabled in the pending block")
 )
 
 // SimulatedBackend implements bind.ContractBackend, simulating a blockchain in
@@ -94,8 +97,9 @@ type SimulatedBackend struct {
 	blockchain *core.BlockChain // Ethereum blockchain to handle the consensus
 
 	mu            sync.Mutex
-	acceptedBlock *types.Block   // Currently accepted block that will be imported on request
-	acceptedState *state.StateDB // Currently accepted state that will be the active on request
+	acceptedBlock *types.Block      // Currently accepted block that will be imported on request
+	acceptedState *state.StateDB    // Currently accepted state that will be the active on request
+	prices        []*streamer.Price // Oracle prices carried by the accepted block
 
 	events *filters.EventSystem // Event system for filtering log events live
 
@@ -108,7 +112,13 @@ type SimulatedBackend struct {
 func NewSimulatedBackendWithDatabase(database ethdb.Database, alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBackend {
 	cpcfg := params.TestChainConfig
 	cpcfg.ChainID = big.NewInt(1337)
-	genesis := core.Genesis{Config: cpcfg, GasLimit: gasLimit, Alloc: alloc}
+	return newSimulatedBackend(database, cpcfg, alloc, gasLimit)
+}
+
+// newSimulatedBackend creates a new binding backend on [database] with a simulated
+// blockchain following [config].
+func newSimulatedBackend(database ethdb.Database, config *params.ChainConfig, alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBackend {
+	genesis := core.Genesis{Config: config, GasLimit: gasLimit, Alloc: alloc}
 	genesis.MustCommit(database)
 	cacheConfig := &core.CacheConfig{}
 	blockchain, _ := core.NewBlockChain(database, cacheConfig, genesis.Config, dummy.NewFaker(), vm.Config{}, common.Hash{})
@@ -164,6 +174,7 @@ func (b *SimulatedBackend) Rollback() {
 }
 
 func (b *SimulatedBackend) rollback(parent *types.Block) {
+	b.prices = nil
 	blocks, _, _ := core.GenerateChain(b.config, parent, dummy.NewFaker(), b.database, 1, 10, func(int, *core.BlockGen) {})
 
 	b.acceptedBlock = blocks[0]
@@ -700,6 +711,9 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	}
 	// Include tx in chain
 	blocks, _, err := core.GenerateChain(b.config, block, dummy.NewETHFaker(), b.database, 1, 10, func(number int, block *core.BlockGen) {
+		if len(b.prices) > 0 {
+			block.SetPrices(b.prices)
+		}
 		for _, tx := range b.acceptedBlock.Transactions() {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
@@ -822,6 +836,9 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 
 	blocks, _, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), dummy.NewFaker(), b.database, 1, 10, func(number int, block *core.BlockGen) {
 		block.OffsetTime(int64(adjustment.Seconds()))
+		if len(b.prices) > 0 {
+			block.SetPrices(b.prices)
+		}
 	})
 	stateDB, _ := b.blockchain.State()
 
@@ -831,6 +848,113 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	return nil
 }
 
+// SetPrices sets the oracle prices carried by the accepted block, replacing any
+// prices set since the last Commit. The prices are written to the state of the
+// accepted block before its transactions, so that calls and transactions see them,
+// and are imported with the block on Commit. Prices of feeds that are not
+// registered or are pushed on-chain are rejected.
+func (b *SimulatedBackend) SetPrices(prices []*streamer.Price) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parentState, err := b.acceptedParentState()
+	if err != nil {
+		return err
+	}
+	for _, price := range prices {
+		if err := b.checkPrice(parentState, price); err != nil {
+			return err
+		}
+	}
+	return b.setPrices(prices)
+}
+
+// SetPrice sets the price of the feed registered for [symbol] in the accepted
+// block, keeping the prices already set for other feeds. [price] is scaled by
+// the decimals the feed is registered with.
+func (b *SimulatedBackend) SetPrice(symbol string, price int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parentState, err := b.acceptedParentState()
+	if err != nil {
+		return err
+	}
+	id, ok := precompile.GetFeedIdBySymbol(parentState, symbol)
+	if !ok {
+		return fmt.Errorf("no feed is registered for symbol %s", symbol)
+	}
+	info, _ := precompile.GetFeedInfo(parentState, id)
+	newPrice := &streamer.Price{Price: price, Slot: b.acceptedBlock.NumberU64(), Symbol: symbol, Decimals: uint(info.Decimals)}
+	if err := b.checkPrice(parentState, newPrice); err != nil {
+		return err
+	}
+
+	prices := make([]*streamer.Price, 0, len(b.prices)+1)
+	for _, p := range b.prices {
+		if p.Symbol != symbol {
+			prices = append(prices, p)
+		}
+	}
+	return b.setPrices(append(prices, newPrice))
+}
+
+// acceptedParentState returns the state the accepted block is built on.
+func (b *SimulatedBackend) acceptedParentState() (*state.StateDB, error) {
+	parent := b.blockchain.GetBlockByHash(b.acceptedBlock.ParentHash())
+	if parent == nil {
+		return nil, errors.New("could not fetch parent")
+	}
+	return b.blockchain.StateAt(parent.Root())
+}
+
+// checkPrice returns an error if [price] cannot be carried by the accepted block
+// built on [parentState].
+func (b *SimulatedBackend) checkPrice(parentState *state.StateDB, price *streamer.Price) error {
+	if !b.config.IsPriceOracle(new(big.Int).SetUint64(b.acceptedBlock.Time())) {
+		return errPriceOracleDisabled
+	}
+	id, ok := precompile.GetFeedIdBySymbol(parentState, price.Symbol)
+	if !ok {
+		return fmt.Errorf("no feed is registered for symbol %s", price.Symbol)
+	}
+	info, _ := precompile.GetFeedInfo(parentState, id)
+	if info.Source == precompile.FeedSourcePushed {
+		return fmt.Errorf("feed %s is pushed on-chain and cannot carry header prices", price.Symbol)
+	}
+	if price.Decimals != uint(info.Decimals) {
+		return fmt.Errorf("price of %s has %d decimals, expected %d", price.Symbol, price.Decimals, info.Decimals)
+	}
+	return nil
+}
+
+// setPrices regenerates the accepted block with [prices] and its transactions.
+func (b *SimulatedBackend) setPrices(prices []*streamer.Price) error {
+	parent := b.blockchain.GetBlockByHash(b.acceptedBlock.ParentHash())
+	if parent == nil {
+		return errors.New("could not fetch parent")
+	}
+	offset := int64(b.acceptedBlock.Time()) - int64(parent.Time()) - 10
+	blocks, _, err := core.GenerateChain(b.config, parent, dummy.NewFaker(), b.database, 1, 10, func(number int, block *core.BlockGen) {
+		if offset != 0 {
+			block.OffsetTime(offset)
+		}
+		block.SetPrices(prices)
+		for _, tx := range b.acceptedBlock.Transactions() {
+			block.AddTxWithChain(b.blockchain, tx)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	stateDB, _ := b.blockchain.State()
+
+	b.prices = prices
+	b.acceptedBlock = blocks[0]
+	b.acceptedState, _ = state.New(b.acceptedBlock.Root(), stateDB.Database(), nil)
+	return nil
+}
+
 // Blockchain returns the underlying blockchain.
 func (b *SimulatedBackend) Blockchain() *core.BlockChain {
 	return b.blockchain
@@ -39,12 +39,15 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/accounts/abi"
 	"github.com/gattaca-com/oracle-evm/accounts/abi/bind"
 	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/interfaces"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 func TestSimulatedBackend(t *testing.T) {
@@ -1362,3 +1365,101 @@ contract Callable {
 // 		t.Errorf("TX included in wrong block: %d", h)
 // 	}
 // }
+
+func TestSetPrices(t *testing.T) {
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+	ethUSD := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	config := *params.TestChainConfig
+	config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, append([]precompile.OracleFeedConfig{
+		{Id: common.Hash(ethUSD), Symbol: "ETH/USD", Decimals: 6},
+	}, params.DefaultOracleFeeds...))
+	sim := newSimulatedBackend(rawdb.NewMemoryDatabase(), &config, core.GenesisAlloc{
+		testAddr: {Balance: new(big.Int).Mul(big.NewInt(10000000000000000), big.NewInt(1000))},
+	}, 10000000)
+	defer sim.Close()
+	bgCtx := context.Background()
+
+	input, _ := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	call := interfaces.CallMsg{From: testAddr, To: &precompile.PriceOracleAddress, Data: input}
+	getPrice := func(accepted bool) int64 {
+		var (
+			res []byte
+			err error
+		)
+		if accepted {
+			res, err = sim.AcceptedCallContract(bgCtx, call)
+		} else {
+			res, err = sim.CallContract(bgCtx, call, nil)
+		}
+		if err != nil {
+			t.Fatalf("could not get price: %v", err)
+		}
+		return new(big.Int).SetBytes(res).Int64()
+	}
+
+	if err := sim.SetPrice("BTC/USD", 1); err == nil || !strings.Contains(err.Error(), "no feed is registered") {
+		t.Errorf("expected an error for an unregistered feed, got %v", err)
+	}
+	if err := sim.SetPrices([]*streamer.Price{{Price: 1, Symbol: "AVAX/USD", Decimals: 6}}); err == nil || !strings.Contains(err.Error(), "expected 8") {
+		t.Errorf("expected an error for mismatched decimals, got %v", err)
+	}
+
+	// Prices are visible in the accepted block before they are committed, along with its transactions.
+	head, _ := sim.HeaderByNumber(bgCtx, nil)
+	gasPrice := new(big.Int).Add(head.BaseFee, big.NewInt(1))
+	tx := types.NewTransaction(0, testAddr, big.NewInt(1000), params.TxGas, gasPrice, nil)
+	signedTx, err := types.SignTx(tx, types.LatestSigner(sim.config), testKey)
+	if err != nil {
+		t.Fatalf("could not sign tx: %v", err)
+	}
+	if err := sim.SendTransaction(bgCtx, signedTx); err != nil {
+		t.Fatalf("could not add tx to pending block: %v", err)
+	}
+	if err := sim.SetPrice("AVAX/USD", 1_500_000_000); err != nil {
+		t.Fatalf("could not set price: %v", err)
+	}
+	if err := sim.SetPrice("ETH/USD", 3_000_000_000); err != nil {
+		t.Fatalf("could not set price: %v", err)
+	}
+	if err := sim.SetPrice("AVAX/USD", 2_000_000_000); err != nil {
+		t.Fatalf("could not set price: %v", err)
+	}
+	if price := getPrice(true); price != 2_000_000_000 {
+		t.Errorf("expected accepted price 2000000000, got %d", price)
+	}
+	if price := getPrice(false); price != 0 {
+		t.Errorf("expected no price before commit, got %d", price)
+	}
+	if len(sim.acceptedBlock.Transactions()) != 1 {
+		t.Errorf("expected the transaction to remain in the accepted block")
+	}
+
+	// Committing imports the block through the state processor.
+	sim.Commit(true)
+	if price := getPrice(false); price != 2_000_000_000 {
+		t.Errorf("expected committed price 2000000000, got %d", price)
+	}
+	block, _ := sim.BlockByNumber(bgCtx, big.NewInt(1))
+	prices := block.GetPrices()
+	if len(prices) != 2 || prices[0].Symbol != "ETH/USD" || prices[0].Price != 3_000_000_000 || prices[1].Symbol != "AVAX/USD" || prices[1].Price != 2_000_000_000 {
+		t.Errorf("unexpected prices in committed block: %v", prices)
+	}
+	stateDB, _ := sim.blockchain.State()
+	if price, ok := precompile.ReadPriceFromState(stateDB, ethUSD); !ok || price.Price != 3_000_000_000 {
+		t.Errorf("expected committed ETH/USD price 3000000000, got %v", price)
+	}
+	receipt, _ := sim.TransactionReceipt(bgCtx, signedTx.Hash())
+	if receipt == nil || receipt.BlockNumber.Uint64() != 1 {
+		t.Errorf("expected the transaction to be committed in block 1")
+	}
+
+	// Prices are cleared after commit, while the state keeps the last written price.
+	sim.Commit(true)
+	block, _ = sim.BlockByNumber(bgCtx, big.NewInt(2))
+	if len(block.GetPrices()) != 0 {
+		t.Errorf("expected no prices in block 2, got %v", block.GetPrices())
+	}
+	if price := getPrice(false); price != 2_000_000_000 {
+		t.Errorf("expected price 2000000000 to persist, got %d", price)
+	}
+}
@@ -0,0 +1,166 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/internal/ethapi"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/rpc"
+)
+
+func TestGenerateBlockWithOracleData(t *testing.T) {
+	sofr := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	chain, _, _ := newChain(t, func(config *params.ChainConfig) {
+		config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, append([]precompile.OracleFeedConfig{
+			{Id: common.Hash(sofr), Symbol: "SOFR", Decimals: 2, Type: precompile.FeedTypeInt256},
+		}, params.DefaultOracleFeeds...))
+	})
+	chain.Start()
+	defer chain.Stop()
+
+	value := &types.FeedValue{Symbol: "SOFR", Value: common.BigToHash(big.NewInt(530)), Decimals: 2}
+	chain.SetFeedValueSource(func() ([]*types.FeedValue, error) {
+		return []*types.FeedValue{value}, nil
+	})
+
+	// A price for the typed feed is ignored rather than failing the block.
+	avaxUSD := &streamer.Price{Price: 1_834_000_000, Slot: 10, Symbol: "AVAX/USD", Decimals: 8}
+	prices, err := streamer.PricesToBytes([]*streamer.Price{
+		avaxUSD,
+		{Price: 1, Slot: 10, Symbol: "SOFR", Decimals: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := chain.GenerateBlock(prices)
+	if err != nil {
+		t.Fatalf("failed to generate block: %v", err)
+	}
+	if values, err := block.GetFeedValues(); err != nil || len(values) != 1 || *values[0] != *value {
+		t.Fatalf("expected the block to carry the feed values of the source, got %v", values)
+	}
+	insertAndAccept(t, chain, block)
+
+	state, err := chain.CurrentState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price, ok := precompile.ReadPriceFromState(state, precompile.AVAX_USD); !ok || *price != *avaxUSD {
+		t.Errorf("expected AVAX/USD price %v, got %v", avaxUSD, price)
+	}
+	stored, ok := precompile.ReadFeedValueFromState(state, sofr)
+	if !ok || stored.Int().Int64() != 530 || stored.Decimals != 2 {
+		t.Errorf("expected SOFR value 530 with 2 decimals, got %v", stored)
+	}
+
+	// Values that validators would reject are dropped from the block rather than failing it.
+	chain.SetFeedValueSource(func() ([]*types.FeedValue, error) {
+		return []*types.FeedValue{value, {Symbol: "BTC/USD", Value: common.BigToHash(common.Big1)}}, nil
+	})
+	block, err = chain.GenerateBlock(prices)
+	if err != nil {
+		t.Fatalf("failed to generate block: %v", err)
+	}
+	if values, err := block.GetFeedValues(); err != nil || len(values) != 0 {
+		t.Fatalf("expected the block to carry no feed values, got %v", values)
+	}
+	insertAndAccept(t, chain, block)
+}
+
+func TestPendingPrices(t *testing.T) {
+	ethUSD := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	clock := &mockable.Clock{}
+	clock.Set(time.Unix(100, 0))
+	chain, _, _ := newChainWithClock(t, clock, func(config *params.ChainConfig) {
+		config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, append([]precompile.OracleFeedConfig{
+			{Id: common.Hash(ethUSD), Symbol: "ETH/USD", Decimals: 6},
+		}, params.DefaultOracleFeeds...))
+	})
+	chain.Start()
+	defer chain.Stop()
+
+	backend := chain.APIBackend()
+	api := ethapi.NewPublicOracleAPI(backend)
+	if _, err := api.GetPendingPrices(context.Background()); err == nil {
+		t.Fatal("expected no pending prices without a price source")
+	}
+
+	prices := []*streamer.Price{
+		{Price: 1_834_000_000, Slot: 10, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: 3_000_000_000, Slot: 11, Symbol: "ETH/USD", Decimals: 6},
+	}
+	pricesBytes, err := streamer.PricesToBytes(prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.SetPriceSource(func() ([]byte, error) { return pricesBytes, nil })
+
+	pending, err := api.GetPendingPrices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get pending prices: %v", err)
+	}
+	if len(pending) != len(prices) {
+		t.Fatalf("expected %d pending prices, got %d", len(prices), len(pending))
+	}
+	for i, price := range prices {
+		if got := pending[i]; got.Symbol != price.Symbol || got.Price.ToInt().Int64() != price.Price || got.Expo != -int32(price.Decimals) || uint64(got.Slot) != price.Slot {
+			t.Errorf("pending price %d: expected %v, got %+v", i, price, got)
+		}
+	}
+
+	// The pending state holds the pending prices, and is reused while the head and the prices do not change.
+	statedb, header, err := backend.StateAndHeaderByNumber(context.Background(), rpc.PendingBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 1 {
+		t.Fatalf("expected pending block 1, got %d", header.Number)
+	}
+	for i, id := range []precompile.PriceFeedId{precompile.AVAX_USD, ethUSD} {
+		if price, ok := precompile.ReadPriceFromState(statedb, id); !ok || *price != *prices[i] {
+			t.Errorf("expected pending price %v, got %v", prices[i], price)
+		}
+	}
+	precompile.WritePriceToState(statedb, &streamer.Price{Price: 1, Slot: 12, Symbol: "ETH/USD", Decimals: 6}, header.Time)
+
+	block, _ := backend.PendingBlockAndReceipts()
+	again, _ := backend.PendingBlockAndReceipts()
+	if block != again {
+		t.Error("expected the pending block to be reused")
+	}
+	clock.Set(time.Unix(101, 0))
+	if later, _ := backend.PendingBlockAndReceipts(); later == nil || later == block || later.Time() != 101 {
+		t.Errorf("expected the pending block to be rebuilt at the new timestamp, got %v", later)
+	}
+	statedb, _, err = backend.StateAndHeaderByNumber(context.Background(), rpc.PendingBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price, _ := precompile.ReadPriceFromState(statedb, ethUSD); price == nil || *price != *prices[1] {
+		t.Errorf("expected the pending state not to be modified by callers, got ETH/USD price %v", price)
+	}
+
+	// A new head rebuilds the pending block.
+	next, err := chain.GenerateBlock(pricesBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	insertAndAccept(t, chain, next)
+	if block, _ := backend.PendingBlockAndReceipts(); block == nil || block.NumberU64() != 2 {
+		t.Errorf("expected pending block 2 after the new head, got %v", block)
+	}
+	if chain.CurrentBlock().NumberU64() != 1 {
+		t.Errorf("expected the pending block not to be inserted")
+	}
+}
@@ -64,6 +64,16 @@ func (self *ETHChain) GenerateBlock(oraclePrices []byte) (*types.Block, error) {
 	return self.backend.Miner().GenerateBlock(oraclePrices)
 }
 
+// SetPriceSource sets the source of the oracle prices applied to the pending block.
+func (self *ETHChain) SetPriceSource(source func() ([]byte, error)) {
+	self.backend.Miner().SetPriceSource(source)
+}
+
+// SetFeedValueSource sets the source of the typed oracle values carried by the generated and pending blocks.
+func (self *ETHChain) SetFeedValueSource(source func() ([]*types.FeedValue, error)) {
+	self.backend.Miner().SetFeedValueSource(source)
+}
+
 func (self *ETHChain) BlockChain() *core.BlockChain {
 	return self.backend.BlockChain()
 }
@@ -50,6 +50,16 @@ func init() {
 }
 
 func NewDefaultChain(t *testing.T) (*ETHChain, chan core.NewTxPoolHeadEvent, <-chan core.NewTxsEvent) {
+	return newChain(t, nil)
+}
+
+// newChain creates a chain whose genesis config is the default one with [configure] applied to it.
+func newChain(t *testing.T, configure func(*params.ChainConfig)) (*ETHChain, chan core.NewTxPoolHeadEvent, <-chan core.NewTxsEvent) {
+	return newChainWithClock(t, &mockable.Clock{}, configure)
+}
+
+// newChainWithClock returns a new chain as newChain does, producing blocks at the time of [clock].
+func newChainWithClock(t *testing.T, clock *mockable.Clock, configure func(*params.ChainConfig)) (*ETHChain, chan core.NewTxPoolHeadEvent, <-chan core.NewTxsEvent) {
 	// configure the chain
 	config := ethconfig.NewDefaultConfig()
 	chainConfig := &params.ChainConfig{
@@ -65,6 +75,9 @@ func NewDefaultChain(t *testing.T) (*ETHChain, chan core.NewTxPoolHeadEvent, <-c
 		IstanbulBlock:       big.NewInt(0),
 		FeeConfig:           params.DefaultFeeConfig,
 	}
+	if configure != nil {
+		configure(chainConfig)
+	}
 
 	config.Genesis = &core.Genesis{
 		Config:     chainConfig,
@@ -86,7 +99,7 @@ func NewDefaultChain(t *testing.T) (*ETHChain, chan core.NewTxPoolHeadEvent, <-c
 		rawdb.NewMemoryDatabase(),
 		eth.DefaultSettings,
 		common.Hash{},
-		&mockable.Clock{},
+		clock,
 	)
 	if err != nil {
 		t.Fatal(err)
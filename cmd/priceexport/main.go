@@ -0,0 +1,158 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// priceexport writes the oracle prices carried by the blocks of a chain database
+// to CSV or JSONL, without running a node.
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/ethdb"
+	"github.com/gattaca-com/oracle-evm/internal/flags"
+	"github.com/gattaca-com/oracle-evm/plugin/evm"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// Git SHA1 commit hash of the release (set via linker flags)
+	gitCommit = ""
+	gitDate   = ""
+
+	app *cli.App
+
+	// Flags needed by priceexport
+	dbFlag = cli.StringFlag{
+		Name:  "db",
+		Usage: "Path to the database to read, which must not be in use by a running node (avalanchego: <db-dir>/<network>/<version>)",
+	}
+	blockchainIDFlag = cli.StringFlag{
+		Name:  "blockchain-id",
+		Usage: "Blockchain ID of the chain, if --db is an avalanchego database rather than a chaindata directory",
+	}
+	feedsFlag = cli.StringFlag{
+		Name:  "feeds",
+		Usage: "Comma separated symbols of the feeds to export (default = all)",
+	}
+	fromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to export",
+	}
+	toFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block to export (default = head block)",
+	}
+	formatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format (csv, jsonl)",
+		Value: core.PriceExportCSV,
+	}
+	outFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "Output file for the prices, gzipped if it ends in .gz (default = stdout)",
+	}
+)
+
+func init() {
+	app = flags.NewApp(gitCommit, gitDate, "oracle price export tool")
+	app.Flags = []cli.Flag{
+		dbFlag,
+		blockchainIDFlag,
+		feedsFlag,
+		fromFlag,
+		toFlag,
+		formatFlag,
+		outFlag,
+	}
+	app.Action = utils.MigrateFlags(priceexport)
+	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
+}
+
+// openDatabase opens the chain database at [path]. If [blockchainID] is set, [path] is the versioned directory
+// of an avalanchego database and the chain database is the one the VM of [blockchainID] stores in it.
+func openDatabase(path string, blockchainID string) (ethdb.Database, error) {
+	if blockchainID == "" {
+		return rawdb.NewLevelDBDatabase(path, 16, 16, "", true)
+	}
+	chainID, err := ids.FromString(blockchainID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blockchain ID %q: %w", blockchainID, err)
+	}
+	baseDB, err := leveldb.New(path, nil, logging.NoLog{})
+	if err != nil {
+		return nil, err
+	}
+	// Mirror the prefixes applied by the avalanchego chain manager and the VM.
+	vmDB := prefixdb.New([]byte("vm"), prefixdb.New(chainID[:], baseDB))
+	return evm.Database{Database: prefixdb.NewNested([]byte("ethdb"), vmDB)}, nil
+}
+
+func priceexport(c *cli.Context) error {
+	if c.GlobalString(dbFlag.Name) == "" {
+		utils.Fatalf("No database specified (--db)")
+	}
+	db, err := openDatabase(c.GlobalString(dbFlag.Name), c.GlobalString(blockchainIDFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	first := c.GlobalUint64(fromFlag.Name)
+	last := c.GlobalUint64(toFlag.Name)
+	if !c.GlobalIsSet(toFlag.Name) {
+		head := rawdb.ReadHeadBlockHash(db)
+		if head == (common.Hash{}) {
+			utils.Fatalf("Database has no head block")
+		}
+		number := rawdb.ReadHeaderNumber(db, head)
+		if number == nil {
+			utils.Fatalf("Head block %s not found", head.Hex())
+		}
+		last = *number
+	}
+	var feeds []string
+	if c.GlobalIsSet(feedsFlag.Name) {
+		feeds = strings.Split(c.GlobalString(feedsFlag.Name), ",")
+	}
+
+	var writer io.Writer = os.Stdout
+	if file := c.GlobalString(outFlag.Name); file != "" {
+		out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			utils.Fatalf("Failed to create output file: %v", err)
+		}
+		defer out.Close()
+
+		writer = out
+		if strings.HasSuffix(file, ".gz") {
+			writer = gzip.NewWriter(writer)
+			defer writer.(*gzip.Writer).Close()
+		}
+	}
+	if err := core.ExportPrices(db, writer, feeds, first, last, c.GlobalString(formatFlag.Name)); err != nil {
+		utils.Fatalf("Failed to export prices: %v", err)
+	}
+	return nil
+}
+
+func main() {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,109 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package commontype
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gattaca-com/oracle-evm/utils"
+)
+
+// FeeConfig specifies the parameters for the dynamic fee algorithm, which determines the gas limit, base fee, and block gas cost of blocks
+// on the network.
+//
+// The dynamic fee algorithm simply increases fees when the network is operating at a utilization level above the target and decreases fees
+// when the network is operating at a utilization level below the target.
+// This struct is used by both the chain config and the fee manager precompile, which is why it lives in its own package.
+type FeeConfig struct {
+	// GasLimit sets the max amount of gas consumed per block.
+	GasLimit *big.Int `json:"gasLimit,omitempty"`
+
+	// TargetBlockRate sets the target rate of block production in seconds.
+	TargetBlockRate uint64 `json:"targetBlockRate,omitempty"`
+
+	// The minimum base fee sets a lower bound on the EIP-1559 base fee of a block.
+	MinBaseFee *big.Int `json:"minBaseFee,omitempty"`
+	// When the dynamic fee algorithm observes that network activity is above/below the [TargetGas], it increases/decreases the base fee proportionally
+	// to how far above/below the target actual network activity is.
+	TargetGas *big.Int `json:"targetGas,omitempty"`
+	// The base fee change denominator controls the rate of base fee change; a higher denominator means a slower change.
+	BaseFeeChangeDenominator *big.Int `json:"baseFeeChangeDenominator,omitempty"`
+
+	// MinBlockGasCost sets the minimum amount of gas to charge for the production of a block.
+	MinBlockGasCost *big.Int `json:"minBlockGasCost,omitempty"`
+	// MaxBlockGasCost sets the maximum amount of gas to charge for the production of a block.
+	MaxBlockGasCost *big.Int `json:"maxBlockGasCost,omitempty"`
+	// BlockGasCostStep determines how much to increase/decrease the block gas cost depending on the amount of time elapsed since the previous block.
+	BlockGasCostStep *big.Int `json:"blockGasCostStep,omitempty"`
+}
+
+// Verify returns an error if any field of [f] is missing, out of range or cannot be stored in a single
+// 32 byte storage slot.
+func (f *FeeConfig) Verify() error {
+	switch {
+	case f.GasLimit == nil:
+		return fmt.Errorf("gasLimit cannot be nil")
+	case f.MinBaseFee == nil:
+		return fmt.Errorf("minBaseFee cannot be nil")
+	case f.TargetGas == nil:
+		return fmt.Errorf("targetGas cannot be nil")
+	case f.BaseFeeChangeDenominator == nil:
+		return fmt.Errorf("baseFeeChangeDenominator cannot be nil")
+	case f.MinBlockGasCost == nil:
+		return fmt.Errorf("minBlockGasCost cannot be nil")
+	case f.MaxBlockGasCost == nil:
+		return fmt.Errorf("maxBlockGasCost cannot be nil")
+	case f.BlockGasCostStep == nil:
+		return fmt.Errorf("blockGasCostStep cannot be nil")
+	}
+
+	switch {
+	case f.GasLimit.Sign() <= 0:
+		return fmt.Errorf("gasLimit = %d cannot be less than or equal to 0", f.GasLimit)
+	case !f.GasLimit.IsUint64():
+		return fmt.Errorf("gasLimit = %d cannot be larger than %d", f.GasLimit, uint64(math.MaxUint64))
+	case f.TargetBlockRate == 0:
+		return fmt.Errorf("targetBlockRate cannot be 0")
+	case f.MinBaseFee.Sign() < 0:
+		return fmt.Errorf("minBaseFee = %d cannot be less than 0", f.MinBaseFee)
+	case f.TargetGas.Sign() <= 0:
+		return fmt.Errorf("targetGas = %d cannot be less than or equal to 0", f.TargetGas)
+	case f.BaseFeeChangeDenominator.Sign() <= 0:
+		return fmt.Errorf("baseFeeChangeDenominator = %d cannot be less than or equal to 0", f.BaseFeeChangeDenominator)
+	case f.MinBlockGasCost.Sign() < 0:
+		return fmt.Errorf("minBlockGasCost = %d cannot be less than 0", f.MinBlockGasCost)
+	case !f.MaxBlockGasCost.IsUint64():
+		return fmt.Errorf("maxBlockGasCost = %d must be between 0 and %d", f.MaxBlockGasCost, uint64(math.MaxUint64))
+	case f.MinBlockGasCost.Cmp(f.MaxBlockGasCost) > 0:
+		return fmt.Errorf("minBlockGasCost = %d cannot be greater than maxBlockGasCost = %d", f.MinBlockGasCost, f.MaxBlockGasCost)
+	case f.BlockGasCostStep.Sign() < 0:
+		return fmt.Errorf("blockGasCostStep = %d cannot be less than 0", f.BlockGasCostStep)
+	}
+
+	for _, value := range []*big.Int{f.MinBaseFee, f.TargetGas, f.BaseFeeChangeDenominator, f.BlockGasCostStep} {
+		if value.BitLen() > common.HashLength*8 {
+			return fmt.Errorf("fee config value %d does not fit in %d bytes", value, common.HashLength)
+		}
+	}
+	return nil
+}
+
+// Equal returns true iff [other] has the same values as [f].
+func (f *FeeConfig) Equal(other *FeeConfig) bool {
+	if other == nil {
+		return false
+	}
+
+	return utils.BigNumEqual(f.GasLimit, other.GasLimit) &&
+		f.TargetBlockRate == other.TargetBlockRate &&
+		utils.BigNumEqual(f.MinBaseFee, other.MinBaseFee) &&
+		utils.BigNumEqual(f.TargetGas, other.TargetGas) &&
+		utils.BigNumEqual(f.BaseFeeChangeDenominator, other.BaseFeeChangeDenominator) &&
+		utils.BigNumEqual(f.MinBlockGasCost, other.MinBlockGasCost) &&
+		utils.BigNumEqual(f.MaxBlockGasCost, other.MaxBlockGasCost) &&
+		utils.BigNumEqual(f.BlockGasCostStep, other.BlockGasCostStep)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
 )
@@ -18,7 +19,8 @@ import (
 // CalcBaseFee takes the previous header and the timestamp of its child block
 // and calculates the expected base fee as well as the encoding of the past
 // pricing information for the child block.
-func CalcBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uint64) ([]byte, *big.Int, error) {
+// [feeConfig] is the fee config in effect for the child block, as returned by GetFeeConfigAt(parent).
+func CalcBaseFee(config *params.ChainConfig, feeConfig *params.FeeConfig, parent *types.Header, timestamp uint64) ([]byte, *big.Int, error) {
 	// If the current block is the first EIP-1559 block, or it is the genesis block
 	// return the initial slice and initial base fee.
 	isSubnetEVM := config.IsSubnetEVM(new(big.Int).SetUint64(parent.Time))
@@ -26,7 +28,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uin
 
 	if !isSubnetEVM || parent.Number.Cmp(common.Big0) == 0 {
 		initialSlice := make([]byte, extraDataSize)
-		minBaseFee := config.GetFeeConfig().MinBaseFee
+		minBaseFee := feeConfig.MinBaseFee
 		return initialSlice, minBaseFee, nil
 	}
 	if len(parent.Extra) != extraDataSize {
@@ -47,9 +49,9 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uin
 
 	// start off with parent's base fee
 	baseFee := new(big.Int).Set(parent.BaseFee)
-	baseFeeChangeDenominator := config.GetFeeConfig().BaseFeeChangeDenominator
+	baseFeeChangeDenominator := feeConfig.BaseFeeChangeDenominator
 
-	parentGasTargetBig := config.GetFeeConfig().TargetGas
+	parentGasTargetBig := feeConfig.TargetGas
 	parentGasTarget := parentGasTargetBig.Uint64()
 
 	// Add in the gas used by the parent block in the correct place
@@ -101,7 +103,7 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uin
 		baseFee.Sub(baseFee, baseFeeDelta)
 	}
 
-	expectedMinBaseFee := config.GetFeeConfig().MinBaseFee
+	expectedMinBaseFee := feeConfig.MinBaseFee
 	baseFee = selectBigWithinBounds(expectedMinBaseFee, baseFee, nil)
 
 	return newRollupWindow, baseFee, nil
@@ -112,11 +114,34 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uin
 // If [timestamp] is less than the timestamp of [parent], then it uses the same timestamp as parent.
 // Warning: This function should only be used in estimation and should not be used when calculating the canonical
 // base fee for a subsequent block.
-func EstimateNextBaseFee(config *params.ChainConfig, parent *types.Header, timestamp uint64) ([]byte, *big.Int, error) {
+func EstimateNextBaseFee(config *params.ChainConfig, feeConfig *params.FeeConfig, parent *types.Header, timestamp uint64) ([]byte, *big.Int, error) {
 	if timestamp < parent.Time {
 		timestamp = parent.Time
 	}
-	return CalcBaseFee(config, parent, timestamp)
+	return CalcBaseFee(config, feeConfig, parent, timestamp)
+}
+
+// CalcUSDMinBaseFee converts the USD minimum base fee of [usdConfig] to wei per gas using [price], the AVAX/USD
+// price in the state of [parent], and returns it bounded by the floor and ceiling of [usdConfig].
+// If [usdConfig] specifies smoothing, the result exceeds the base fee of [parent] by at most 1/SmoothingDenominator
+// of it, so that a sudden price drop raises fees over several blocks instead of at once.
+// Returns nil if [price] cannot be used for the conversion.
+func CalcUSDMinBaseFee(usdConfig *params.USDFeeConfig, parent *types.Header, price *streamer.Price) *big.Int {
+	if price == nil || price.Price <= 0 {
+		return nil
+	}
+	// [price] is the USD value of 10^18 wei scaled by 10^decimals, so the minimum base fee in wei is
+	// attoUSD * 10^decimals / price.
+	minBaseFee := new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(uint64(price.Decimals)), nil)
+	minBaseFee.Mul(minBaseFee, usdConfig.MinBaseFee)
+	minBaseFee.Div(minBaseFee, big.NewInt(price.Price))
+	minBaseFee = selectBigWithinBounds(usdConfig.MinBaseFeeFloor, minBaseFee, usdConfig.MinBaseFeeCeiling)
+
+	if usdConfig.SmoothingDenominator != nil && parent.BaseFee != nil {
+		maxStep := math.BigMax(new(big.Int).Div(parent.BaseFee, usdConfig.SmoothingDenominator), common.Big1)
+		minBaseFee = selectBigWithinBounds(nil, minBaseFee, new(big.Int).Add(parent.BaseFee, maxStep))
+	}
+	return minBaseFee
 }
 
 // selectBigWithinBounds returns [value] if it is within the bounds:
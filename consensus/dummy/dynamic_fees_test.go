@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
 	"github.com/stretchr/testify/assert"
@@ -200,7 +201,7 @@ func testDynamicFeesStaysWithinRange(t *testing.T, test test) {
 	for index, block := range blocks[1:] {
 		tc := params.TestChainConfig
 		tc.FeeConfig.MinBaseFee = params.TestMinBaseFee
-		nextExtraData, nextBaseFee, err := CalcBaseFee(params.TestChainConfig, header, block.timestamp)
+		nextExtraData, nextBaseFee, err := CalcBaseFee(params.TestChainConfig, params.TestChainConfig.GetFeeConfig(), header, block.timestamp)
 		if err != nil {
 			t.Fatalf("Failed to calculate base fee at index %d: %s", index, err)
 		}
@@ -313,6 +314,74 @@ func TestSelectBigWithinBounds(t *testing.T) {
 	}
 }
 
+func TestCalcUSDMinBaseFee(t *testing.T) {
+	type test struct {
+		usdConfig     *params.USDFeeConfig
+		parentBaseFee *big.Int
+		price         *streamer.Price
+		expected      *big.Int
+	}
+
+	// At 20 USD per AVAX (8 decimals), 5*10^-7 USD per gas is 25 gwei.
+	avaxUsd := &streamer.Price{Price: 2_000_000_000, Symbol: "AVAX/USD", Decimals: 8}
+	minBaseFeeUSD := big.NewInt(500_000_000_000)
+	tests := map[string]test{
+		"converts usd to wei": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD},
+			price:     avaxUsd,
+			expected:  big.NewInt(25_000_000_000),
+		},
+		"price halves": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD},
+			price:     &streamer.Price{Price: 1_000_000_000, Symbol: "AVAX/USD", Decimals: 8},
+			expected:  big.NewInt(50_000_000_000),
+		},
+		"below floor": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD, MinBaseFeeFloor: big.NewInt(30_000_000_000)},
+			price:     avaxUsd,
+			expected:  big.NewInt(30_000_000_000),
+		},
+		"above ceiling": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD, MinBaseFeeCeiling: big.NewInt(20_000_000_000)},
+			price:     avaxUsd,
+			expected:  big.NewInt(20_000_000_000),
+		},
+		"smoothed increase": {
+			usdConfig:     &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD, SmoothingDenominator: big.NewInt(10)},
+			parentBaseFee: big.NewInt(10_000_000_000),
+			price:         avaxUsd,
+			expected:      big.NewInt(11_000_000_000),
+		},
+		"smoothing within step": {
+			usdConfig:     &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD, SmoothingDenominator: big.NewInt(10)},
+			parentBaseFee: big.NewInt(24_000_000_000),
+			price:         avaxUsd,
+			expected:      big.NewInt(25_000_000_000),
+		},
+		"no price": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD},
+		},
+		"non-positive price": {
+			usdConfig: &params.USDFeeConfig{MinBaseFee: minBaseFeeUSD},
+			price:     &streamer.Price{Price: -1, Symbol: "AVAX/USD", Decimals: 8},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parent := &types.Header{BaseFee: test.parentBaseFee}
+			minBaseFee := CalcUSDMinBaseFee(test.usdConfig, parent, test.price)
+			if test.expected == nil {
+				assert.Nil(t, minBaseFee)
+				return
+			}
+			if minBaseFee.Cmp(test.expected) != 0 {
+				t.Fatalf("Expected (%d), found (%d)", test.expected, minBaseFee)
+			}
+		})
+	}
+}
+
 func TestCalcBlockGasCost(t *testing.T) {
 	tests := map[string]struct {
 		parentBlockGasCost      *big.Int
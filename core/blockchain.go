@@ -67,6 +67,8 @@ const (
 	badBlockLimit      = 10
 	TriesInMemory      = 128
 
+	feeConfigCacheLimit = 256
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
 	// Changelog:
@@ -163,6 +165,8 @@ type BlockChain struct {
 	blockCache    *lru.Cache // Cache for the most recent entire blocks
 	txLookupCache *lru.Cache // Cache for the most recent transaction lookup data.
 
+	feeConfigCache *lru.Cache // Cache for the most recent fee configs read from the state, keyed by block hash.
+
 	quit    chan struct{}  // blockchain quit channel
 	wg      sync.WaitGroup // chain processing wait group for shutting down
 	running int32          // 0 if chain is running, 1 when stopped
@@ -194,6 +198,7 @@ func NewBlockChain(
 	receiptsCache, _ := lru.New(receiptsCacheLimit)
 	blockCache, _ := lru.New(blockCacheLimit)
 	txLookupCache, _ := lru.New(txLookupCacheLimit)
+	feeConfigCache, _ := lru.New(feeConfigCacheLimit)
 	badBlocks, _ := lru.New(badBlockLimit)
 
 	bc := &BlockChain{
@@ -213,6 +218,8 @@ func NewBlockChain(
 		vmConfig:      vmConfig,
 		badBlocks:     badBlocks,
 		senderCacher:  newTxSenderCacher(runtime.NumCPU()),
+
+		feeConfigCache: feeConfigCache,
 	}
 	bc.validator = NewBlockValidator(chainConfig, bc, engine)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
@@ -643,9 +650,12 @@ func (bc *BlockChain) Accept(block *types.Block) error {
 		}
 	}
 
-	// Update transaction lookup index
+	// Update transaction lookup index and price history
 	batch := bc.db.NewBatch()
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	if err := WritePriceHistory(bc.chainConfig, batch, block.Header()); err != nil {
+		log.Warn("Failed to index block prices", "number", block.Number(), "hash", block.Hash(), "err", err)
+	}
 	if err := batch.Write(); err != nil {
 		return fmt.Errorf("failed to write tx lookup entries batch: %w", err)
 	}
@@ -27,15 +27,20 @@
 package core
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/gattaca-com/oracle-evm/consensus"
+	"github.com/gattaca-com/oracle-evm/consensus/dummy"
 	"github.com/gattaca-com/oracle-evm/core/rawdb"
 	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/state/snapshot"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 // CurrentHeader retrieves the current head header of the canonical chain. The
@@ -260,6 +265,60 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
+// GetFeeConfigAt returns the fee config in effect for the child of [parent].
+// Once the fee manager precompile is enabled at the time of [parent], this is the fee config stored
+// in the state of [parent], otherwise it is the fee config of the chain config. Once the minimum base
+// fee is pegged to USD, it is converted with the AVAX/USD price in the state of [parent].
+func (bc *BlockChain) GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error) {
+	if !feeConfigReadsState(bc.chainConfig, parent) {
+		return bc.chainConfig.GetFeeConfig(), nil
+	}
+	// The pegged minimum base fee depends on the base fee of [parent], so the cache is keyed
+	// by block hash rather than by state root.
+	if cached, ok := bc.feeConfigCache.Get(parent.Hash()); ok {
+		return cached.(*params.FeeConfig), nil
+	}
+	feeConfig, err := feeConfigAt(bc.chainConfig, parent, bc.StateAt)
+	if err != nil {
+		return nil, err
+	}
+	bc.feeConfigCache.Add(parent.Hash(), feeConfig)
+	return feeConfig, nil
+}
+
+// feeConfigReadsState returns true if the fee config in effect for the child of [parent] depends on the
+// state of [parent].
+func feeConfigReadsState(config *params.ChainConfig, parent *types.Header) bool {
+	parentTime := new(big.Int).SetUint64(parent.Time)
+	return config.IsFeeConfigManager(parentTime) || config.IsUSDFeePegged(parentTime)
+}
+
+// feeConfigAt reads the fee config in effect for the child of [parent] from the state of [parent], which is
+// opened with [stateAt]. If no fee config has been stored by the fee manager precompile, the fee config of
+// [config] remains in effect. If no AVAX/USD price is available, the minimum base fee is not pegged to USD.
+func feeConfigAt(config *params.ChainConfig, parent *types.Header, stateAt func(common.Hash) (*state.StateDB, error)) (*params.FeeConfig, error) {
+	statedb, err := stateAt(parent.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee config at block %d (%s): %w", parent.Number, parent.Hash(), err)
+	}
+	parentTime := new(big.Int).SetUint64(parent.Time)
+	feeConfig := config.GetFeeConfig()
+	if config.IsFeeConfigManager(parentTime) {
+		if storedFeeConfig, ok := precompile.GetStoredFeeConfig(statedb); ok {
+			feeConfig = storedFeeConfig
+		}
+	}
+	if config.IsUSDFeePegged(parentTime) && config.IsPriceOracle(parentTime) {
+		price, _ := precompile.ReadPriceFromState(statedb, precompile.AVAX_USD)
+		if minBaseFee := dummy.CalcUSDMinBaseFee(config.USDFeeConfig, parent, price); minBaseFee != nil {
+			peggedFeeConfig := *feeConfig
+			peggedFeeConfig.MinBaseFee = minBaseFee
+			feeConfig = &peggedFeeConfig
+		}
+	}
+	return feeConfig, nil
+}
+
 // Engine retrieves the blockchain's consensus engine.
 func (bc *BlockChain) Engine() consensus.Engine { return bc.engine }
 
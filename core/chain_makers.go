@@ -31,6 +31,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/consensus"
 	"github.com/gattaca-com/oracle-evm/consensus/dummy"
 	"github.com/gattaca-com/oracle-evm/core/state"
@@ -38,6 +39,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/ethdb"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 // BlockGen creates blocks for testing.
@@ -56,6 +58,9 @@ type BlockGen struct {
 
 	config *params.ChainConfig
 	engine consensus.Engine
+
+	// triggersApplied is set once the price triggers fired at the start of the block have been executed.
+	triggersApplied bool
 }
 
 // SetCoinbase sets the coinbase of the generated block.
@@ -88,6 +93,69 @@ func (b *BlockGen) SetDifficulty(diff *big.Int) {
 	b.header.Difficulty = diff
 }
 
+// SetPrices sets the oracle prices carried in the header of the generated block.
+// While the price oracle is enabled, the prices are written to the state, as done
+// by the StateProcessor.
+//
+// SetPrices must be called before adding transactions, and after OffsetTime if it
+// is used.
+func (b *BlockGen) SetPrices(prices []*streamer.Price) {
+	if len(b.txs) > 0 || b.triggersApplied {
+		panic("prices must be set before adding transactions")
+	}
+	if len(b.header.Prices) > 0 {
+		panic("prices can only be set once")
+	}
+	pricesBytes, err := streamer.PricesToBytes(prices)
+	if err != nil {
+		panic(err)
+	}
+	b.header.Prices = pricesBytes
+
+	if b.config.IsPriceOracle(new(big.Int).SetUint64(b.header.Time)) {
+		for _, price := range prices {
+			precompile.WritePriceToState(b.statedb, price, b.header.Time)
+		}
+	}
+}
+
+// SetFeedValues sets the typed oracle values carried in the header of the generated
+// block. While the price oracle is enabled, the values are written to the state, as
+// done by the StateProcessor.
+//
+// SetFeedValues must be called before adding transactions, so that the values are
+// written to the state before the callbacks of the price triggers are executed, as
+// done when the block is processed.
+func (b *BlockGen) SetFeedValues(values []*types.FeedValue) {
+	if len(b.txs) > 0 || b.triggersApplied {
+		panic("feed values must be set before adding transactions")
+	}
+	if len(b.header.FeedValues) > 0 {
+		panic("feed values can only be set once")
+	}
+	valuesBytes, err := types.EncodeFeedValues(values)
+	if err != nil {
+		panic(err)
+	}
+	b.header.FeedValues = valuesBytes
+
+	if b.config.IsPriceOracle(new(big.Int).SetUint64(b.header.Time)) {
+		for _, value := range values {
+			precompile.WriteFeedValueToState(b.statedb, value.Symbol, value.Value, value.Decimals, b.header.Time)
+		}
+	}
+}
+
+// applyPriceTriggers executes the price triggers fired at the start of the generated
+// block, as done by the StateProcessor before the first transaction of every block.
+func (b *BlockGen) applyPriceTriggers() {
+	if b.triggersApplied {
+		return
+	}
+	b.triggersApplied = true
+	ApplyPriceTriggers(b.config, NewEVMBlockContext(b.header, nil, &b.header.Coinbase), b.header, b.statedb, vm.Config{})
+}
+
 // AddTx adds a transaction to the generated block. If no coinbase has
 // been set, the block's coinbase is set to the zero address.
 //
@@ -112,6 +180,7 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 	if b.gasPool == nil {
 		b.SetCoinbase(common.Address{})
 	}
+	b.applyPriceTriggers()
 	b.statedb.Prepare(tx.Hash(), len(b.txs))
 	receipt, err := ApplyTransaction(b.config, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
 	if err != nil {
@@ -210,7 +279,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		config = params.TestChainConfig
 	}
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
-	chainreader := &fakeChainReader{config: config}
+	chainreader := &fakeChainReader{config: config, db: db}
 	genblock := func(i int, parent *types.Block, statedb *state.StateDB) (*types.Block, types.Receipts, error) {
 		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine}
 		b.header = makeHeader(chainreader, config, parent, gap, statedb, b.engine)
@@ -219,6 +288,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if gen != nil {
 			gen(i, b)
 		}
+		b.applyPriceTriggers()
 		if b.engine != nil {
 			// Finalize and seal the block
 			block, err := b.engine.FinalizeAndAssemble(chainreader, b.header, parent.Header(), statedb, b.txs, b.uncles, b.receipts)
@@ -265,7 +335,11 @@ func makeHeader(chain consensus.ChainReader, config *params.ChainConfig, parent
 	timestamp := new(big.Int).SetUint64(time)
 	var gasLimit uint64
 	if config.IsSubnetEVM(timestamp) {
-		gasLimit = config.GetFeeConfig().GasLimit.Uint64()
+		feeConfig, err := chain.GetFeeConfigAt(parent.Header())
+		if err != nil {
+			panic(err)
+		}
+		gasLimit = feeConfig.GasLimit.Uint64()
 	} else {
 		gasLimit = CalcGasLimit(parent.GasUsed(), parent.GasLimit(), parent.GasLimit(), parent.GasLimit())
 	}
@@ -285,8 +359,11 @@ func makeHeader(chain consensus.ChainReader, config *params.ChainConfig, parent
 		Time:     time,
 	}
 	if chain.Config().IsSubnetEVM(timestamp) {
-		var err error
-		header.Extra, header.BaseFee, err = dummy.CalcBaseFee(chain.Config(), parent.Header(), time)
+		feeConfig, err := chain.GetFeeConfigAt(parent.Header())
+		if err != nil {
+			panic(err)
+		}
+		header.Extra, header.BaseFee, err = dummy.CalcBaseFee(chain.Config(), feeConfig, parent.Header(), time)
 		if err != nil {
 			panic(err)
 		}
@@ -296,6 +373,7 @@ func makeHeader(chain consensus.ChainReader, config *params.ChainConfig, parent
 
 type fakeChainReader struct {
 	config *params.ChainConfig
+	db     ethdb.Database
 }
 
 // Config returns the chain configuration.
@@ -303,6 +381,17 @@ func (cr *fakeChainReader) Config() *params.ChainConfig {
 	return cr.config
 }
 
+// GetFeeConfigAt returns the fee config in effect for the child of [parent], reading
+// the state of [parent] from the database the chain is generated in.
+func (cr *fakeChainReader) GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error) {
+	if !feeConfigReadsState(cr.config, parent) {
+		return cr.config.GetFeeConfig(), nil
+	}
+	return feeConfigAt(cr.config, parent, func(root common.Hash) (*state.StateDB, error) {
+		return state.New(root, state.NewDatabase(cr.db), nil)
+	})
+}
+
 func (cr *fakeChainReader) CurrentHeader() *types.Header                            { return nil }
 func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header           { return nil }
 func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
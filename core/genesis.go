@@ -220,6 +220,9 @@ func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig
 	if err := newcfg.CheckConfigForkOrder(); err != nil {
 		return newcfg, err
 	}
+	if err := newcfg.Verify(); err != nil {
+		return newcfg, err
+	}
 	storedcfg := rawdb.ReadChainConfig(db, stored)
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
@@ -275,7 +278,7 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 
 	genesisTimestamp := new(big.Int).SetUint64(g.Timestamp)
 	// Configure any stateful precompiles that should be enabled in the genesis.
-	g.Config.CheckConfigurePrecompiles(nil, genesisTimestamp, statedb)
+	g.Config.CheckConfigurePrecompiles(nil, genesisTimestamp, new(big.Int).SetUint64(g.Number), statedb)
 
 	// Do cusotm allocation after airdrop in case an address shows up in standard
 	// allocation
@@ -337,6 +340,9 @@ func (g *Genesis) Commit(db ethdb.Database) (*types.Block, error) {
 	if err := config.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
 	rawdb.WriteBlock(db, block)
 	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), nil)
 	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
@@ -34,16 +34,15 @@ import (
 
 	"github.com/gattaca-com/oracle-evm/consensus/dummy"
 	"github.com/gattaca-com/oracle-evm/core/rawdb"
-
-	// "github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/ethdb"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 
-	// "github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
-	// "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/assert"
 )
 
 func setupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig, common.Hash, error) {
@@ -172,58 +171,64 @@ func TestSetupGenesis(t *testing.T) {
 	}
 }
 
-// func TestStatefulPrecompilesConfigure(t *testing.T) {
-// 	type test struct {
-// 		getConfig   func() *params.ChainConfig             // Return the config that enables the stateful precompile at the genesis for the test
-// 		assertState func(t *testing.T, sdb *state.StateDB) // Check that the stateful precompiles were configured correctly
-// 	}
-
-// 	addr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
-
-// 	// Test suite to ensure that stateful precompiles are configured correctly in the genesis.
-// 	for name, test := range map[string]test{
-// 		"allow list enabled in genesis": {
-// 			getConfig: func() *params.ChainConfig {
-// 				config := *params.TestChainConfig
-// 				config.ContractDeployerAllowListConfig = precompile.ContractDeployerAllowListConfig{
-// 					AllowListConfig: precompile.AllowListConfig{
-// 						BlockTimestamp:  big.NewInt(0),
-// 						AllowListAdmins: []common.Address{addr},
-// 					},
-// 				}
-// 				return &config
-// 			},
-// 			assertState: func(t *testing.T, sdb *state.StateDB) {
-// 				assert.Equal(t, precompile.AllowListAdmin, precompile.GetContractDeployerAllowListStatus(sdb, addr), "unexpected allow list status for modified address")
-// 				assert.Equal(t, uint64(1), sdb.GetNonce(precompile.ContractDeployerAllowListAddress))
-// 			},
-// 		},
-// 	} {
-// 		t.Run(name, func(t *testing.T) {
-// 			config := test.getConfig()
-
-// 			genesis := &Genesis{
-// 				Config: config,
-// 				Alloc: GenesisAlloc{
-// 					{1}: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{{1}: {1}}},
-// 				},
-// 				GasLimit: config.FeeConfig.GasLimit.Uint64(),
-// 			}
-
-// 			db := rawdb.NewMemoryDatabase()
-// 			_, err := SetupGenesisBlock(db, genesis)
-// 			if err != nil {
-// 				t.Fatal(err)
-// 			}
-
-// 			genesisBlock := genesis.ToBlock(nil)
-// 			genesisRoot := genesisBlock.Root()
-
-// 			statedb, err := state.New(genesisRoot, state.NewDatabase(db), nil)
-// 			if err != nil {
-// 				t.Fatal(err)
-// 			}
-// 			test.assertState(t, statedb)
-// 		})
-// 	}
-// }
+func TestStatefulPrecompilesConfigure(t *testing.T) {
+	type test struct {
+		getConfig   func() *params.ChainConfig             // Return the config that enables the stateful precompile at the genesis for the test
+		assertState func(t *testing.T, sdb *state.StateDB) // Check that the stateful precompiles were configured correctly
+	}
+
+	addr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	// Test suite to ensure that stateful precompiles are configured correctly in the genesis.
+	for name, test := range map[string]test{
+		"allow list enabled in genesis": {
+			getConfig: func() *params.ChainConfig {
+				config := *params.TestChainConfig
+				config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr})
+				return &config
+			},
+			assertState: func(t *testing.T, sdb *state.StateDB) {
+				assert.Equal(t, precompile.AllowListAdmin, precompile.GetContractDeployerAllowListStatus(sdb, addr), "unexpected allow list status for modified address")
+				assert.Equal(t, uint64(1), sdb.GetNonce(precompile.ContractDeployerAllowListAddress))
+			},
+		},
+		"native minter enabled in genesis": {
+			getConfig: func() *params.ChainConfig {
+				config := *params.TestChainConfig
+				config.ContractNativeMinterConfig = precompile.NewContractNativeMinterConfig(big.NewInt(0), []common.Address{addr})
+				return &config
+			},
+			assertState: func(t *testing.T, sdb *state.StateDB) {
+				assert.Equal(t, precompile.AllowListAdmin, precompile.GetContractNativeMinterStatus(sdb, addr), "unexpected minter status for modified address")
+				assert.Equal(t, uint64(1), sdb.GetNonce(precompile.ContractNativeMinterAddress))
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := test.getConfig()
+
+			genesis := &Genesis{
+				Config: config,
+				Alloc: GenesisAlloc{
+					{1}: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{{1}: {1}}},
+				},
+				GasLimit: config.FeeConfig.GasLimit.Uint64(),
+			}
+
+			db := rawdb.NewMemoryDatabase()
+			_, err := SetupGenesisBlock(db, genesis)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			genesisBlock := genesis.ToBlock(nil)
+			genesisRoot := genesisBlock.Root()
+
+			statedb, err := state.New(genesisRoot, state.NewDatabase(db), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.assertState(t, statedb)
+		})
+	}
+}
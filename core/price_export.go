@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/ethdb"
+)
+
+// Formats supported by ExportPrices.
+const (
+	PriceExportCSV   = "csv"
+	PriceExportJSONL = "jsonl"
+)
+
+// priceExportColumns are the columns of a price export, in CSV order.
+var priceExportColumns = []string{"block", "timestamp", "symbol", "price", "expo", "slot"}
+
+// priceExportRow is a row of a price export in the JSONL format.
+type priceExportRow struct {
+	Block     uint64 `json:"block"`
+	Timestamp uint64 `json:"timestamp"`
+	Symbol    string `json:"symbol"`
+	Price     int64  `json:"price"`
+	Expo      int64  `json:"expo"`
+	Slot      uint64 `json:"slot"`
+}
+
+// ExportPrices writes the prices carried by the canonical blocks from [first] to [last] inclusive in [db] to [w],
+// one row per price, in the given format. Only the prices of [symbols] are exported, or all of them if
+// [symbols] is empty. The expo of a price is its negated decimals.
+func ExportPrices(db ethdb.Reader, w io.Writer, symbols []string, first uint64, last uint64, format string) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	var write func(row *priceExportRow) error
+	switch format {
+	case PriceExportCSV:
+		writer := csv.NewWriter(w)
+		defer writer.Flush()
+		if err := writer.Write(priceExportColumns); err != nil {
+			return err
+		}
+		write = func(row *priceExportRow) error {
+			return writer.Write([]string{
+				strconv.FormatUint(row.Block, 10),
+				strconv.FormatUint(row.Timestamp, 10),
+				row.Symbol,
+				strconv.FormatInt(row.Price, 10),
+				strconv.FormatInt(row.Expo, 10),
+				strconv.FormatUint(row.Slot, 10),
+			})
+		}
+	case PriceExportJSONL:
+		encoder := json.NewEncoder(w)
+		write = func(row *priceExportRow) error {
+			return encoder.Encode(row)
+		}
+	default:
+		return fmt.Errorf("unsupported price export format %q", format)
+	}
+	filter := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		filter[symbol] = struct{}{}
+	}
+	log.Info("Exporting prices of batch of blocks", "count", last-first+1)
+
+	start, reported := time.Now(), time.Now()
+	for nr := first; nr <= last; nr++ {
+		hash := rawdb.ReadCanonicalHash(db, nr)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		header := rawdb.ReadHeader(db, hash, nr)
+		if header == nil {
+			return fmt.Errorf("export failed on #%d: header not found", nr)
+		}
+		prices, err := types.DecodePrices(header.Prices)
+		if err != nil {
+			return fmt.Errorf("export failed on #%d: %w", nr, err)
+		}
+		for _, price := range prices {
+			if _, ok := filter[price.Symbol]; len(filter) > 0 && !ok {
+				continue
+			}
+			if err := write(&priceExportRow{
+				Block:     nr,
+				Timestamp: header.Time,
+				Symbol:    price.Symbol,
+				Price:     price.Price,
+				Expo:      -int64(price.Decimals),
+				Slot:      price.Slot,
+			}); err != nil {
+				return err
+			}
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting prices", "exported", nr-first, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	return nil
+}
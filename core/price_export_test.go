@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportPrices(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	symbols := [][]string{{"AVAX/USD", "ETH/USD"}, {"BTC/USD"}, {"ETH/USD", "BTC/USD", "AVAX/USD"}}
+	for i, blockSymbols := range symbols {
+		number := uint64(i + 1)
+		var blockPrices []*streamer.Price
+		for j, symbol := range blockSymbols {
+			blockPrices = append(blockPrices, &streamer.Price{Price: int64(100*number) + int64(j), Slot: 10 + number, Symbol: symbol, Decimals: 8})
+		}
+		prices, err := streamer.PricesToBytes(blockPrices)
+		require.NoError(t, err)
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Time: 1000 + number, Difficulty: big.NewInt(1), Prices: prices}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), number)
+	}
+
+	var csv bytes.Buffer
+	require.NoError(t, ExportPrices(db, &csv, nil, 1, 3, PriceExportCSV))
+	assert.Equal(t, "block,timestamp,symbol,price,expo,slot\n"+
+		"1,1001,AVAX/USD,100,-8,11\n"+
+		"1,1001,ETH/USD,101,-8,11\n"+
+		"2,1002,BTC/USD,200,-8,12\n"+
+		"3,1003,ETH/USD,300,-8,13\n"+
+		"3,1003,BTC/USD,301,-8,13\n"+
+		"3,1003,AVAX/USD,302,-8,13\n", csv.String())
+
+	var jsonl bytes.Buffer
+	require.NoError(t, ExportPrices(db, &jsonl, []string{"AVAX/USD"}, 2, 3, PriceExportJSONL))
+	assert.Equal(t, `{"block":3,"timestamp":1003,"symbol":"AVAX/USD","price":302,"expo":-8,"slot":13}`+"\n", jsonl.String())
+
+	assert.Error(t, ExportPrices(db, &csv, nil, 1, 4, PriceExportCSV), "missing block")
+	assert.Error(t, ExportPrices(db, &csv, nil, 3, 1, PriceExportCSV), "inverted range")
+	assert.Error(t, ExportPrices(db, &csv, nil, 1, 3, "xml"), "unsupported format")
+}
@@ -0,0 +1,99 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/ethdb"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+)
+
+const (
+	// priceHistoryThrottling is the time to wait between backfilling two consecutive
+	// sections of the price history index.
+	priceHistoryThrottling = 100 * time.Millisecond
+)
+
+// WritePriceHistory indexes the prices carried by [header] under the ids of the feeds they are written to
+// by the price oracle. Feeds are resolved from [config] rather than from state, so that the index can be
+// backfilled on pruned nodes. Prices of pushed feeds and of unregistered symbols are not indexed, as they
+// are not written to state either.
+func WritePriceHistory(config *params.ChainConfig, db ethdb.KeyValueWriter, header *types.Header) error {
+	timestamp := new(big.Int).SetUint64(header.Time)
+	if !config.IsPriceOracle(timestamp) || len(header.Prices) == 0 {
+		return nil
+	}
+	prices, err := types.DecodePrices(header.Prices)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]common.Hash)
+	for _, feed := range config.GetOracleFeeds(timestamp) {
+		if feed.Source == precompile.FeedSourceStreamed {
+			ids[feed.Symbol] = feed.Id
+		}
+	}
+	number := header.Number.Uint64()
+	for _, price := range prices {
+		if id, ok := ids[price.Symbol]; ok {
+			rawdb.WritePriceHistory(db, id, number, price)
+		}
+	}
+	return nil
+}
+
+// PriceHistoryIndexer implements a core.ChainIndexer, backfilling the per-feed
+// price history of the canonical chain from the prices carried by its headers.
+type PriceHistoryIndexer struct {
+	config *params.ChainConfig // chain config resolving the feeds of each header
+	db     ethdb.Database      // database instance to write the price history into
+	batch  ethdb.Batch         // batch of the section being processed currently
+}
+
+// NewPriceHistoryIndexer returns a chain indexer that backfills the price history
+// of the canonical chain, for blocks accepted before the history was written on
+// accept.
+func NewPriceHistoryIndexer(db ethdb.Database, config *params.ChainConfig, size, confirms uint64) *ChainIndexer {
+	backend := &PriceHistoryIndexer{
+		config: config,
+		db:     db,
+	}
+	table := rawdb.NewTable(db, string(rawdb.PriceHistoryIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, priceHistoryThrottling, "pricehistory")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new price history section.
+func (p *PriceHistoryIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	p.batch = p.db.NewBatch()
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the prices of a new header
+// into the index.
+func (p *PriceHistoryIndexer) Process(ctx context.Context, header *types.Header) error {
+	if err := WritePriceHistory(p.config, p.batch, header); err != nil {
+		log.Warn("Failed to index block prices", "number", header.Number, "hash", header.Hash(), "err", err)
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the price history of
+// the section into the database.
+func (p *PriceHistoryIndexer) Commit() error {
+	return p.batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (p *PriceHistoryIndexer) Prune(threshold uint64) error {
+	return nil
+}
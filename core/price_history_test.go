@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePriceHistory(t *testing.T) {
+	avaxUSD, ethUSD := common.Hash(precompile.AVAX_USD), common.BigToHash(big.NewInt(1))
+	config := *params.TestChainConfig
+	config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: avaxUSD, Symbol: "AVAX/USD", Decimals: 8},
+		{Id: ethUSD, Symbol: "ETH/USD", Decimals: 8},
+	})
+
+	prices := []*streamer.Price{
+		{Price: 1834000000, Slot: 10, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: 7, Slot: 11, Symbol: "BTC/USD", Decimals: 8},
+		{Price: 300000000000, Slot: 12, Symbol: "ETH/USD", Decimals: 8},
+	}
+	data, err := streamer.PricesToBytes(prices)
+	require.NoError(t, err)
+
+	db := rawdb.NewMemoryDatabase()
+	header := &types.Header{Number: big.NewInt(3), Time: 1000, Difficulty: big.NewInt(1), Prices: data}
+	require.NoError(t, WritePriceHistory(&config, db, header))
+
+	assert.Equal(t, prices[0], rawdb.ReadPriceHistory(db, avaxUSD, 3))
+	assert.Equal(t, prices[2], rawdb.ReadPriceHistory(db, ethUSD, 3))
+	assert.Nil(t, rawdb.ReadPriceHistory(db, common.BigToHash(big.NewInt(2)), 3), "unregistered symbol")
+
+	header = &types.Header{Number: big.NewInt(4), Time: 1001, Difficulty: big.NewInt(1), Prices: data[:len(data)-1]}
+	assert.Error(t, WritePriceHistory(&config, db, header), "malformed prices")
+}
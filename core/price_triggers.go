@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+)
+
+// priceTriggerLogsHash is the transaction hash under which the logs of the price trigger callbacks are
+// recorded until they are taken out of the state.
+var priceTriggerLogsHash = crypto.Keccak256Hash([]byte("priceTriggerCallbacks"))
+
+// ApplyPriceTriggers executes the callbacks of the price triggers whose condition is met by the prices in
+// [statedb]. It must be called right after the prices of [header] have been written to [statedb] and before
+// any transaction of the block is applied.
+//
+// Triggers fire in the order of the active list. Before its callback runs, each trigger is read again and its
+// condition checked against the current state, since a callback run earlier in the block may have cancelled it.
+// Each callback is a call from the owner of the trigger to its
+// target, run with the gas limit prepaid at registration and a zero gas price. A trigger is removed before its
+// callback runs, whether or not the callback succeeds. Triggers whose gas limit does not fit in the gas left
+// for callbacks in this block remain active and are checked again in the next block.
+// Callbacks do not produce receipts and do not count towards the gas used by the block. The logs emitted
+// by the callbacks are removed from [statedb] and returned, so that they are not attributed to the first
+// transaction of the block and do not shift the log indexes of the transactions of the block.
+func ApplyPriceTriggers(config *params.ChainConfig, blockContext vm.BlockContext, header *types.Header, statedb *state.StateDB, cfg vm.Config) []*types.Log {
+	timestamp := new(big.Int).SetUint64(header.Time)
+	triggerConfig := config.GetActivePriceTriggerConfig(timestamp)
+	if triggerConfig == nil {
+		return nil
+	}
+	fired := precompile.FiredPriceTriggers(statedb)
+	if len(fired) == 0 {
+		return nil
+	}
+
+	var (
+		gasLeft     = triggerConfig.GetMaxGasPerBlock()
+		precompiles = vm.ActivePrecompiles(config.AvalancheRules(header.Number, timestamp))
+		vmenv       = vm.NewEVM(blockContext, vm.TxContext{GasPrice: new(big.Int)}, statedb, config, cfg)
+	)
+	for _, candidate := range fired {
+		trigger, ok := precompile.GetPriceTrigger(statedb, candidate.Id)
+		if !ok {
+			continue
+		}
+		if price, ok := precompile.ReadPriceFromState(statedb, trigger.Feed); !ok || !trigger.Fires(price.Price) {
+			continue
+		}
+		if trigger.GasLimit > gasLeft {
+			continue
+		}
+		gasLeft -= trigger.GasLimit
+		precompile.RemovePriceTrigger(statedb, trigger.Id)
+
+		statedb.Prepare(priceTriggerLogsHash, 0)
+		statedb.PrepareAccessList(trigger.Owner, &trigger.Target, precompiles, nil)
+		vmenv.Reset(vm.TxContext{Origin: trigger.Owner, GasPrice: new(big.Int)}, statedb)
+		if _, _, err := vmenv.Call(vm.AccountRef(trigger.Owner), trigger.Target, trigger.Data, trigger.GasLimit, new(big.Int)); err != nil {
+			log.Debug("price trigger callback failed", "id", trigger.Id, "target", trigger.Target, "err", err)
+		}
+		statedb.Finalise(true)
+	}
+	return statedb.TakeLogs(priceTriggerLogsHash)
+}
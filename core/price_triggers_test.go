@@ -0,0 +1,297 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/consensus/dummy"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPriceTriggerTestState returns a state with the price oracle and price triggers configured by [config].
+func newPriceTriggerTestState(t *testing.T, config *params.ChainConfig) *state.StateDB {
+	stateDb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	precompile.Configure(config.PriceOracleConfig, stateDb, common.Big0)
+	precompile.Configure(config.PriceTriggerConfig, stateDb, common.Big0)
+	return stateDb
+}
+
+func registerPriceTrigger(t *testing.T, stateDb *state.StateDB, owner common.Address, trigger *precompile.PriceTrigger) (uint64, error) {
+	accessibleState := &mockAccessibleState{state: stateDb, blockContext: &mockBlockContext{blockNumber: common.Big1}}
+	input := precompile.PackRegisterTriggerInput(trigger)
+	dataWords := uint64(len(trigger.Data)+31) / 32
+	suppliedGas := precompile.RegisterTriggerGasCost + dataWords*20_000 + trigger.GasLimit
+	ret, remainingGas, err := precompile.PriceTriggerPrecompile.Run(accessibleState, owner, precompile.PriceTriggerAddress, input, suppliedGas, false)
+	if err != nil {
+		return 0, err
+	}
+	assert.Equal(t, uint64(0), remainingGas)
+	return new(big.Int).SetBytes(ret).Uint64(), nil
+}
+
+func TestPriceTriggerRun(t *testing.T) {
+	config := *params.TestChainConfig
+	config.PriceTriggerConfig = precompile.NewPriceTriggerConfig(big.NewInt(0), 0)
+	stateDb := newPriceTriggerTestState(t, &config)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000def")
+	accessibleState := &mockAccessibleState{state: stateDb, blockContext: &mockBlockContext{blockNumber: common.Big1}}
+	contract := precompile.PriceTriggerPrecompile
+
+	trigger := &precompile.PriceTrigger{
+		Feed:      precompile.AVAX_USD,
+		Direction: precompile.PriceTriggerBelow,
+		Threshold: -5,
+		Target:    common.HexToAddress("0x0000000000000000000000000000000000001234"),
+		GasLimit:  100_000,
+		Data:      common.Hex2Bytes("a9059cbb000000000000000000000000000000000000000000000000000000000000abcd"),
+	}
+	id, err := registerPriceTrigger(t, stateDb, owner, trigger)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), id)
+	assert.Equal(t, uint64(1), precompile.GetActivePriceTriggerCount(stateDb))
+
+	stored, ok := precompile.GetPriceTrigger(stateDb, id)
+	require.True(t, ok)
+	expected := *trigger
+	expected.Owner = owner
+	assert.Equal(t, &expected, stored)
+
+	ret, _, err := contract.Run(accessibleState, other, precompile.PriceTriggerAddress, precompile.PackGetTriggerInput(id), precompile.GetTriggerGasCost, true)
+	require.NoError(t, err)
+	assert.Equal(t, owner.Hash().Bytes(), ret[:32])
+	assert.Equal(t, common.Big1, new(big.Int).SetBytes(ret[6*32:]))
+
+	// Triggers can only be registered for registered feeds, with a bounded gas limit and calldata.
+	unknownFeed := *trigger
+	unknownFeed.Feed = precompile.PriceFeedId(common.BigToHash(big.NewInt(42)))
+	_, err = registerPriceTrigger(t, stateDb, owner, &unknownFeed)
+	assert.ErrorIs(t, err, precompile.ErrUnknownFeed)
+	tooMuchGas := *trigger
+	tooMuchGas.GasLimit = precompile.MaxPriceTriggerGasLimit + 1
+	_, err = registerPriceTrigger(t, stateDb, owner, &tooMuchGas)
+	assert.ErrorContains(t, err, "gas limit")
+	tooMuchData := *trigger
+	tooMuchData.Data = make([]byte, precompile.MaxPriceTriggerDataLen+1)
+	_, err = registerPriceTrigger(t, stateDb, owner, &tooMuchData)
+	assert.ErrorContains(t, err, "calldata cannot exceed")
+
+	// The gas limit of the callback is prepaid.
+	_, _, err = contract.Run(accessibleState, owner, precompile.PriceTriggerAddress, precompile.PackRegisterTriggerInput(trigger), precompile.RegisterTriggerGasCost+3*20_000, false)
+	assert.ErrorIs(t, err, vmerrs.ErrOutOfGas)
+	_, _, err = contract.Run(accessibleState, owner, precompile.PriceTriggerAddress, precompile.PackRegisterTriggerInput(trigger), precompile.RegisterTriggerGasCost, true)
+	assert.ErrorIs(t, err, vmerrs.ErrWriteProtection)
+
+	// Only the owner can cancel a trigger.
+	_, _, err = contract.Run(accessibleState, other, precompile.PriceTriggerAddress, precompile.PackCancelTriggerInput(id), precompile.CancelTriggerGasCost+3*20_000, false)
+	assert.ErrorIs(t, err, precompile.ErrNotTriggerOwner)
+	_, _, err = contract.Run(accessibleState, owner, precompile.PriceTriggerAddress, precompile.PackCancelTriggerInput(id), precompile.CancelTriggerGasCost+3*20_000, false)
+	require.NoError(t, err)
+	_, ok = precompile.GetPriceTrigger(stateDb, id)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), precompile.GetActivePriceTriggerCount(stateDb))
+	_, _, err = contract.Run(accessibleState, owner, precompile.PriceTriggerAddress, precompile.PackCancelTriggerInput(id), precompile.CancelTriggerGasCost, false)
+	assert.ErrorIs(t, err, precompile.ErrUnknownTrigger)
+
+	ret, _, err = contract.Run(accessibleState, other, precompile.PriceTriggerAddress, precompile.PackGetTriggerInput(id), precompile.GetTriggerGasCost, true)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 7*32), ret)
+}
+
+func TestApplyPriceTriggers(t *testing.T) {
+	config := *params.TestChainConfig
+	config.PriceTriggerConfig = precompile.NewPriceTriggerConfig(big.NewInt(0), precompile.MaxPriceTriggerGasLimit)
+	stateDb := newPriceTriggerTestState(t, &config)
+
+	// Each target stores the first word of its calldata in slot 0.
+	// CALLDATALOAD(0) PUSH1 0 SSTORE STOP
+	targetCode := common.Hex2Bytes("60003560005500")
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	first := common.HexToAddress("0x0000000000000000000000000000000000001111")
+	second := common.HexToAddress("0x0000000000000000000000000000000000002222")
+	stateDb.SetCode(first, targetCode)
+	stateDb.SetCode(second, targetCode)
+
+	register := func(target common.Address, direction precompile.PriceTriggerDirection, threshold int64) uint64 {
+		id, err := registerPriceTrigger(t, stateDb, owner, &precompile.PriceTrigger{
+			Feed:      precompile.AVAX_USD,
+			Direction: direction,
+			Threshold: threshold,
+			Target:    target,
+			GasLimit:  precompile.MaxPriceTriggerGasLimit,
+			Data:      common.BigToHash(big.NewInt(7)).Bytes(),
+		})
+		require.NoError(t, err)
+		return id
+	}
+	firstId := register(first, precompile.PriceTriggerBelow, 1_500)
+	secondId := register(second, precompile.PriceTriggerAbove, 1_000)
+
+	header := &types.Header{Number: big.NewInt(1), Time: 10, Difficulty: common.Big0}
+	blockContext := vm.BlockContext{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		BlockNumber: header.Number,
+		Time:        new(big.Int).SetUint64(header.Time),
+		Difficulty:  header.Difficulty,
+	}
+	applyPrice := func(price int64) {
+		require.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: price, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}, header.Time))
+		ApplyPriceTriggers(&config, blockContext, header, stateDb, vm.Config{})
+	}
+
+	// Only the second trigger fires, and the first remains active.
+	applyPrice(2_000)
+	assert.Equal(t, common.BigToHash(big.NewInt(7)), stateDb.GetState(second, common.Hash{}))
+	assert.Equal(t, common.Hash{}, stateDb.GetState(first, common.Hash{}))
+	_, ok := precompile.GetPriceTrigger(stateDb, secondId)
+	assert.False(t, ok)
+	_, ok = precompile.GetPriceTrigger(stateDb, firstId)
+	assert.True(t, ok)
+
+	// Both triggers fire, but the gas available to callbacks only fits one of them per block.
+	thirdId := register(second, precompile.PriceTriggerBelow, 1_500)
+	stateDb.SetState(second, common.Hash{}, common.Hash{})
+	applyPrice(1_000)
+	assert.Equal(t, common.BigToHash(big.NewInt(7)), stateDb.GetState(first, common.Hash{}))
+	assert.Equal(t, common.Hash{}, stateDb.GetState(second, common.Hash{}))
+	_, ok = precompile.GetPriceTrigger(stateDb, thirdId)
+	assert.True(t, ok)
+
+	applyPrice(1_000)
+	assert.Equal(t, common.BigToHash(big.NewInt(7)), stateDb.GetState(second, common.Hash{}))
+	assert.Equal(t, uint64(0), precompile.GetActivePriceTriggerCount(stateDb))
+
+	// Triggers are not executed while the precompile is disabled.
+	register(first, precompile.PriceTriggerBelow, 1_500)
+	config.PriceTriggerConfig = precompile.NewPriceTriggerConfig(big.NewInt(100), 0)
+	applyPrice(1_000)
+	assert.Equal(t, uint64(1), precompile.GetActivePriceTriggerCount(stateDb))
+}
+
+func TestApplyPriceTriggersCancelled(t *testing.T) {
+	config := *params.TestChainConfig
+	config.PriceTriggerConfig = precompile.NewPriceTriggerConfig(big.NewInt(0), 2*precompile.MaxPriceTriggerGasLimit)
+	stateDb := newPriceTriggerTestState(t, &config)
+
+	// The canceller forwards its calldata to the price trigger precompile.
+	// CALLDATASIZE PUSH1 0 PUSH1 0 CALLDATACOPY PUSH1 0 PUSH1 0 CALLDATASIZE PUSH1 0 PUSH1 0 PUSH20 <precompile> GAS CALL STOP
+	canceller := common.HexToAddress("0x0000000000000000000000000000000000001111")
+	stateDb.SetCode(canceller, common.Hex2Bytes("36600060003760006000366000600073"+common.Bytes2Hex(precompile.PriceTriggerAddress.Bytes())+"5af100"))
+	// CALLDATALOAD(0) PUSH1 0 SSTORE STOP
+	target := common.HexToAddress("0x0000000000000000000000000000000000002222")
+	stateDb.SetCode(target, common.Hex2Bytes("60003560005500"))
+
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	cancellerId, err := registerPriceTrigger(t, stateDb, owner, &precompile.PriceTrigger{
+		Feed:      precompile.AVAX_USD,
+		Direction: precompile.PriceTriggerAbove,
+		Threshold: 1_000,
+		Target:    canceller,
+		GasLimit:  precompile.MaxPriceTriggerGasLimit,
+		Data:      precompile.PackCancelTriggerInput(1),
+	})
+	require.NoError(t, err)
+	// The second trigger is owned by the canceller, so that its callback can cancel it.
+	targetId, err := registerPriceTrigger(t, stateDb, canceller, &precompile.PriceTrigger{
+		Feed:      precompile.AVAX_USD,
+		Direction: precompile.PriceTriggerAbove,
+		Threshold: 1_000,
+		Target:    target,
+		GasLimit:  precompile.MaxPriceTriggerGasLimit,
+		Data:      common.BigToHash(big.NewInt(7)).Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), targetId)
+
+	header := &types.Header{Number: big.NewInt(1), Time: 10, Difficulty: common.Big0}
+	blockContext := vm.BlockContext{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		BlockNumber: header.Number,
+		Time:        new(big.Int).SetUint64(header.Time),
+		Difficulty:  header.Difficulty,
+	}
+	require.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 2_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}, header.Time))
+	require.Len(t, precompile.FiredPriceTriggers(stateDb), 2)
+
+	// Both triggers fire, but the first callback cancels the second trigger before it is executed.
+	ApplyPriceTriggers(&config, blockContext, header, stateDb, vm.Config{})
+	assert.Equal(t, common.Hash{}, stateDb.GetState(target, common.Hash{}))
+	_, ok := precompile.GetPriceTrigger(stateDb, cancellerId)
+	assert.False(t, ok)
+	_, ok = precompile.GetPriceTrigger(stateDb, targetId)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), precompile.GetActivePriceTriggerCount(stateDb))
+}
+
+func TestGenerateChainPriceTriggers(t *testing.T) {
+	config := *params.TestChainConfig
+	config.PriceTriggerConfig = precompile.NewPriceTriggerConfig(big.NewInt(0), precompile.MaxPriceTriggerGasLimit)
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	// PUSH1 0 PUSH1 0 LOG0 STOP
+	logger := common.HexToAddress("0x0000000000000000000000000000000000003333")
+	db := rawdb.NewMemoryDatabase()
+	genesis := (&Genesis{
+		Config: &config,
+		Alloc: GenesisAlloc{
+			sender: {Balance: big.NewInt(params.Ether)},
+			logger: {Code: common.Hex2Bytes("60006000a000"), Balance: common.Big0},
+		},
+	}).MustCommit(db)
+
+	// CALLDATALOAD(0) PUSH1 0 SSTORE PUSH1 0 PUSH1 0 LOG0 STOP
+	target := common.HexToAddress("0x0000000000000000000000000000000000002222")
+	blocks, receipts, err := GenerateChain(&config, genesis, dummy.NewFaker(), db, 2, 10, func(i int, b *BlockGen) {
+		if i != 0 {
+			tx, err := types.SignTx(types.NewTransaction(b.TxNonce(sender), logger, common.Big0, 50_000, b.BaseFee(), nil), types.HomesteadSigner{}, key)
+			require.NoError(t, err)
+			b.AddTx(tx)
+			return
+		}
+		b.SetPrices([]*streamer.Price{{Price: 2_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}})
+		// The start of the block is processed before the trigger is registered, as if by a transaction.
+		b.applyPriceTriggers()
+		b.statedb.SetCode(target, common.Hex2Bytes("60003560005560006000a000"))
+		_, err := registerPriceTrigger(t, b.statedb, common.HexToAddress("0xabc"), &precompile.PriceTrigger{
+			Feed:      precompile.AVAX_USD,
+			Direction: precompile.PriceTriggerAbove,
+			Threshold: 1_000,
+			Target:    target,
+			GasLimit:  precompile.MaxPriceTriggerGasLimit,
+			Data:      common.BigToHash(big.NewInt(7)).Bytes(),
+		})
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	// The trigger registered in the first block fires at the start of the second, which carries no prices.
+	require.Empty(t, blocks[1].Header().Prices)
+	stateDb, err := state.New(blocks[1].Root(), state.NewDatabase(db), nil)
+	require.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(7)), stateDb.GetState(target, common.Hash{}))
+	assert.Equal(t, uint64(0), precompile.GetActivePriceTriggerCount(stateDb))
+
+	// The log of the callback is not attributed to the first transaction and does not shift its log index.
+	require.Len(t, receipts[1], 1)
+	require.Len(t, receipts[1][0].Logs, 1)
+	assert.Equal(t, logger, receipts[1][0].Logs[0].Address)
+	assert.Equal(t, uint(0), receipts[1][0].Logs[0].Index)
+	assert.Equal(t, uint(0), receipts[1][0].Logs[0].TxIndex)
+}
@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/ethdb"
+)
+
+// PriceHistoryEntry is the price of a feed carried by the canonical block with
+// the given number.
+type PriceHistoryEntry struct {
+	Number uint64
+	Price  *streamer.Price
+}
+
+// ReadPriceHistory retrieves the price of the feed [id] carried by the canonical
+// block [number], or nil if the block did not carry a price for the feed.
+func ReadPriceHistory(db ethdb.KeyValueReader, id common.Hash, number uint64) *streamer.Price {
+	data, _ := db.Get(priceHistoryKey(id, number))
+	if len(data) != common.HashLength {
+		return nil
+	}
+	price, err := streamer.UnmarshallPrice(data)
+	if err != nil {
+		log.Error("Invalid price history entry", "id", id, "number", number, "err", err)
+		return nil
+	}
+	return price
+}
+
+// ReadPriceHistoryRange retrieves the prices of the feed [id] carried by the
+// canonical blocks from [from] to [to] inclusive, in increasing block order.
+func ReadPriceHistoryRange(db ethdb.Iteratee, id common.Hash, from uint64, to uint64) []PriceHistoryEntry {
+	prefix := append(priceHistoryPrefix, id.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var entries []PriceHistoryEntry
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		price, err := streamer.UnmarshallPrice(it.Value())
+		if err != nil {
+			log.Error("Invalid price history entry", "id", id, "number", number, "err", err)
+			continue
+		}
+		entries = append(entries, PriceHistoryEntry{Number: number, Price: price})
+	}
+	if it.Error() != nil {
+		log.Error("Failed to iterate price history", "id", id, "err", it.Error())
+	}
+	return entries
+}
+
+// WritePriceHistory stores the price of the feed [id] carried by the canonical
+// block [number].
+func WritePriceHistory(db ethdb.KeyValueWriter, id common.Hash, number uint64, price *streamer.Price) {
+	data, err := streamer.MarshallPrice(price)
+	if err != nil {
+		log.Crit("Failed to encode price history entry", "err", err)
+	}
+	if err := db.Put(priceHistoryKey(id, number), data); err != nil {
+		log.Crit("Failed to store price history entry", "err", err)
+	}
+}
+
+// DeletePriceHistory removes the price of the feed [id] carried by the canonical
+// block [number].
+func DeletePriceHistory(db ethdb.KeyValueWriter, id common.Hash, number uint64) {
+	if err := db.Delete(priceHistoryKey(id, number)); err != nil {
+		log.Crit("Failed to delete price history entry", "err", err)
+	}
+}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+)
+
+// Tests that the price history of a feed can be stored, retrieved by block and
+// by block range, and deleted, without mixing up the history of other feeds.
+func TestPriceHistoryStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	btc, eth := common.HexToHash("0x01"), common.HexToHash("0x02")
+	for number := uint64(1); number <= 5; number++ {
+		WritePriceHistory(db, btc, number, &streamer.Price{Price: int64(100 * number), Slot: number, Symbol: "BTC/USD", Decimals: 8})
+	}
+	WritePriceHistory(db, eth, 3, &streamer.Price{Price: 7, Slot: 3, Symbol: "ETH/USD", Decimals: 8})
+
+	if price := ReadPriceHistory(db, btc, 3); price == nil || price.Price != 300 || price.Symbol != "BTC/USD" || price.Decimals != 8 {
+		t.Fatalf("unexpected price at block 3: %+v", price)
+	}
+	if price := ReadPriceHistory(db, btc, 6); price != nil {
+		t.Fatalf("unexpected price at block 6: %+v", price)
+	}
+
+	entries := ReadPriceHistoryRange(db, btc, 2, 4)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if number := uint64(i + 2); entry.Number != number || entry.Price.Price != int64(100*number) {
+			t.Fatalf("entry %d: unexpected entry %d: %+v", i, entry.Number, entry.Price)
+		}
+	}
+	if entries := ReadPriceHistoryRange(db, eth, 0, 10); len(entries) != 1 || entries[0].Number != 3 {
+		t.Fatalf("unexpected entries of other feed: %+v", entries)
+	}
+
+	DeletePriceHistory(db, btc, 3)
+	if price := ReadPriceHistory(db, btc, 3); price != nil {
+		t.Fatalf("deleted price still present: %+v", price)
+	}
+	if entries := ReadPriceHistoryRange(db, btc, 1, 5); len(entries) != 4 {
+		t.Fatalf("expected 4 entries after deletion, got %d", len(entries))
+	}
+}
@@ -74,12 +74,14 @@ var (
 	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
+	priceHistoryPrefix    = []byte("p") // priceHistoryPrefix + feed id + num (uint64 big endian) -> price
 
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
-	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	BloomBitsIndexPrefix    = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	PriceHistoryIndexPrefix = []byte("iP") // PriceHistoryIndexPrefix is the data table of the price history indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -160,6 +162,11 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// priceHistoryKey = priceHistoryPrefix + feed id + num (uint64 big endian)
+func priceHistoryKey(id common.Hash, number uint64) []byte {
+	return append(append(priceHistoryPrefix, id.Bytes()...), encodeBlockNumber(number)...)
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -225,6 +225,16 @@ func (s *StateDB) GetLogs(hash common.Hash, blockHash common.Hash) []*types.Log
 	return logs
 }
 
+// TakeLogs removes the logs recorded under [hash] from the state and returns them, so that the logs
+// recorded afterwards are indexed as if they had never been added. The logs of [hash] must be the
+// last logs recorded and the state must have been finalised since they were added.
+func (s *StateDB) TakeLogs(hash common.Hash) []*types.Log {
+	logs := s.logs[hash]
+	delete(s.logs, hash)
+	s.logSize -= uint(len(logs))
+	return logs
+}
+
 func (s *StateDB) Logs() []*types.Log {
 	var logs []*types.Log
 	for _, lgs := range s.logs {
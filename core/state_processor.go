@@ -27,6 +27,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -78,16 +79,9 @@ func (p *StateProcessor) Process(block *types.Block, parent *types.Header, state
 		timestamp   = new(big.Int).SetUint64(header.Time)
 	)
 
-	// Configure any stateful precompiles that should go into effect during this block.
-	p.config.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time), timestamp, statedb)
-
-	// write prices from block header to stateDB
-	prices := block.GetPrices()
-	for _, price := range prices {
-		precompile.WritePriceToState(statedb, price)
-	}
-
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
+	blockContext.Prices = ApplyBlockPrelude(p.config, block, parent, blockContext, statedb, cfg)
+
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
@@ -111,6 +105,66 @@ func (p *StateProcessor) Process(block *types.Block, parent *types.Header, state
 	return receipts, allLogs, *usedGas, nil
 }
 
+// ApplyBlockPrelude applies the state changes made at the start of [block], before any of its
+// transactions: it configures the stateful precompiles that go into effect during the block,
+// writes the prices and typed values carried in its header while the price oracle is enabled
+// and executes the callbacks of the price triggers fired by those prices.
+//
+// It returns the cache of the header prices written to state, which contexts executing the
+// transactions of [block] on top of [statedb] can carry to serve oracle reads from memory.
+func ApplyBlockPrelude(config *params.ChainConfig, block *types.Block, parent *types.Header, blockContext vm.BlockContext, statedb *state.StateDB, cfg vm.Config) precompile.PriceCache {
+	prices := ApplyBlockOracleData(config, block, parent, statedb)
+	blockContext.Prices = prices
+	ApplyPriceTriggers(config, blockContext, block.Header(), statedb, cfg)
+	return prices
+}
+
+// ApplyBlockOracleData applies the part of the prelude of [block] that does not execute any
+// code: it configures the stateful precompiles that go into effect during [block] and writes
+// the prices and typed values carried in its header while the price oracle is enabled.
+// It is meant for previews of a block, such as the pending block, which should not pay for
+// the callbacks of the price triggers.
+func ApplyBlockOracleData(config *params.ChainConfig, block *types.Block, parent *types.Header, statedb *state.StateDB) precompile.PriceCache {
+	timestamp := new(big.Int).SetUint64(block.Time())
+	config.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time), timestamp, block.Number(), statedb)
+
+	var prices precompile.PriceCache
+	if config.IsPriceOracle(timestamp) {
+		prices = precompile.WriteHeaderPrices(statedb, block.GetPrices(), block.Time())
+		// The values of accepted blocks are checked by VerifyFeedValues, previews skip invalid ones.
+		values, _ := block.GetFeedValues()
+		for _, value := range values {
+			precompile.WriteFeedValueToState(statedb, value.Symbol, value.Value, value.Decimals, block.Time())
+		}
+	}
+	return prices
+}
+
+// VerifyFeedValues returns an error if the typed values carried in the header of [block] cannot be
+// decoded, or if any of them is not a valid value of a typed feed registered with the price oracle
+// once the stateful precompiles that go into effect during [block] are configured. [statedb] holds
+// the state of [parent] and is modified by that configuration.
+func VerifyFeedValues(config *params.ChainConfig, block *types.Block, parent *types.Header, statedb *state.StateDB) error {
+	values, err := block.GetFeedValues()
+	if err != nil {
+		return fmt.Errorf("invalid feed values: %w", err)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	timestamp := new(big.Int).SetUint64(block.Time())
+	if !config.IsPriceOracle(timestamp) {
+		return errors.New("feed values carried while the price oracle is disabled")
+	}
+	config.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time), timestamp, block.Number(), statedb)
+	for _, value := range values {
+		if _, _, err := precompile.CheckFeedValue(statedb, value.Symbol, value.Value, value.Decimals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
 	// Create a new context to be used in the EVM environment.
 	txContext := NewEVMTxContext(msg)
@@ -37,6 +37,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/vmerrs"
 )
 
@@ -246,6 +247,13 @@ func (st *StateTransition) preCheck() error {
 		if vm.IsProhibited(st.msg.From()) {
 			return fmt.Errorf("%w: address %v", vmerrs.ErrAddrProhibited, st.msg.From())
 		}
+		// Check that the sender is on the tx allow list if enabled
+		if st.evm.ChainConfig().IsTxAllowList(st.evm.Context.Time) {
+			txAllowListRole := precompile.GetTxAllowListStatus(st.state, st.msg.From())
+			if !txAllowListRole.IsEnabled() {
+				return fmt.Errorf("%w: %s", precompile.ErrSenderAddressNotAllowListed, st.msg.From())
+			}
+		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsSubnetEVM(st.evm.Context.Time) {
@@ -6,10 +6,16 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/core/rawdb"
 	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/params"
 	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+	"github.com/stretchr/testify/assert"
 )
 
 type TestPrecompileAccessibleState struct {
@@ -20,16 +26,31 @@ func (t TestPrecompileAccessibleState) GetStateDB() precompile.StateDB {
 	return t.db
 }
 
-func (t TestPrecompileAccessibleState) BlockTime() *big.Int {
-	return big.NewInt(0)
+func (t TestPrecompileAccessibleState) GetBlockContext() precompile.BlockContext {
+	return &mockBlockContext{blockNumber: big.NewInt(0), timestamp: 0}
 }
 
-func TestPriceOracleSetAndGetPrice(t *testing.T) {
-	db := rawdb.NewMemoryDatabase()
-	stateDb, err := state.New(common.Hash{}, state.NewDatabase(db), nil)
+func (t TestPrecompileAccessibleState) AddLog(addr common.Address, topics []common.Hash, data []byte) {
+}
+
+// newPriceOracleTestState returns an empty state with the price oracle configured with [config], if any.
+func newPriceOracleTestState(t *testing.T, config *precompile.PriceOracleConfig) *state.StateDB {
+	t.Helper()
+	stateDb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	if config != nil {
+		if err := config.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		precompile.Configure(config, stateDb, common.Big0)
+	}
+	return stateDb
+}
+
+func TestPriceOracleSetAndGetPrice(t *testing.T) {
+	stateDb := newPriceOracleTestState(t, precompile.NewPriceOracleConfig(big.NewInt(0), nil, params.DefaultOracleFeeds))
 	testPreCompileAccessibleState := TestPrecompileAccessibleState{stateDb}
 	contract := precompile.CreateNativeGetPriceerPrecompile(precompile.PriceOracleAddress)
 
@@ -40,7 +61,7 @@ func TestPriceOracleSetAndGetPrice(t *testing.T) {
 		Decimals: 8,
 	}
 
-	err = precompile.WritePriceToState(stateDb, &sampleBtcAvaxVal)
+	err := precompile.WritePriceToState(stateDb, &sampleBtcAvaxVal, 0)
 
 	if err != nil {
 		t.Fatal(err)
@@ -76,3 +97,803 @@ func TestPriceOracleSetAndGetPrice(t *testing.T) {
 		t.Errorf("Data was not stored or retreived correctly.\nExpected %+v. Returned %+v", price, sampleBtcAvaxVal.Price)
 	}
 }
+
+func TestPriceOracleWarmAndColdReads(t *testing.T) {
+	stateDb := newPriceOracleTestState(t, nil)
+	testPreCompileAccessibleState := TestPrecompileAccessibleState{stateDb}
+	schedule := precompile.OracleGasSchedule{
+		GetPrice:    precompile.FunctionGasCost{Cold: 4_000, Warm: 300},
+		GetDecimals: precompile.FunctionGasCost{Cold: 3_000, Warm: 200},
+	}
+	contract := precompile.CreatePriceOraclePrecompile(precompile.PriceOracleAddress, schedule)
+
+	input, err := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first read of a feed within a transaction is cold.
+	_, remainingGas, err := contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(50_000-4_000), remainingGas)
+
+	// Any later read of the same feed, through any function, is warm.
+	_, remainingGas, err = contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(50_000-300), remainingGas)
+
+	decimalsInput := append(precompile.CalculateFunctionSelector("getDecimals(uint256)"), precompile.AVAX_USD.Bytes()...)
+	_, remainingGas, err = contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, decimalsInput, 50_000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(50_000-200), remainingGas)
+
+	// Reverting to a snapshot taken before the first read makes the feed cold again.
+	snapshot := stateDb.Snapshot()
+	otherFeed := precompile.BytesToPriceFeedId([]byte{1})
+	otherInput, err := precompile.PackGetPriceInput(&otherFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, otherInput, 50_000, false); err != nil {
+		t.Fatal(err)
+	}
+	stateDb.RevertToSnapshot(snapshot)
+	_, remainingGas, err = contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, otherInput, 50_000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(50_000-4_000), remainingGas)
+
+	// Running out of gas on a cold read does not warm the feed.
+	coldFeed := precompile.BytesToPriceFeedId([]byte{2})
+	coldInput, err := precompile.PackGetPriceInput(&coldFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = contract.Run(testPreCompileAccessibleState, common.Address{}, precompile.PriceOracleAddress, coldInput, 1_000, false); err == nil {
+		t.Fatal("expected out of gas error")
+	}
+	_, slotWarm := stateDb.SlotInAccessList(precompile.PriceOracleAddress, common.Hash(coldFeed))
+	assert.False(t, slotWarm)
+}
+
+func TestPriceOracleFunctionGasCosts(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6},
+	})
+	config.GasSchedule = &precompile.OracleGasSchedule{
+		GetPriceScaled: precompile.FunctionGasCost{Cold: 9_000, Warm: 800},
+		Convert:        precompile.FunctionGasCost{Cold: 6_000, Warm: 600},
+	}
+	stateDb := newPriceOracleTestState(t, config)
+	for _, price := range []*streamer.Price{
+		{Price: 1_834_000_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: 1_800_000_000, Slot: 1, Symbol: "ETH/USD", Decimals: 6},
+	} {
+		if err := precompile.WritePriceToState(stateDb, price, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accessibleState := TestPrecompileAccessibleState{stateDb}
+	contract := config.Contract()
+
+	getPrice, _ := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	getPriceNoOlderThan, _ := precompile.PackGetPriceNoOlderThanInput(&precompile.AVAX_USD, 100)
+	getPriceScaled, _ := precompile.PackGetPriceScaledInput(&precompile.AVAX_USD, 18)
+	convert, _ := precompile.PackConvertInput(common.Big1, &precompile.AVAX_USD, &ethUsd, 0)
+	// Repricing some functions leaves the others at their default cost.
+	for name, test := range map[string]struct {
+		input []byte
+		cost  uint64
+	}{
+		"getPrice":            {getPrice, precompile.GetPriceGasCost},
+		"getPriceNoOlderThan": {getPriceNoOlderThan, precompile.GetPriceGasCost},
+		"getPriceScaled":      {getPriceScaled, 9_000},
+		"convert":             {convert, 2 * 6_000},
+	} {
+		stateDb.Prepare(common.Hash{}, 0)
+		_, remainingGas, err := contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, test.input, 50_000, true)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, 50_000-test.cost, remainingGas, name)
+		}
+	}
+
+	other := *config
+	other.GasSchedule = &precompile.OracleGasSchedule{
+		GetPriceScaled: precompile.FunctionGasCost{Cold: 9_000, Warm: 800},
+		Convert:        precompile.FunctionGasCost{Cold: 6_000, Warm: 500},
+	}
+	assert.False(t, config.Equal(&other))
+	other.GasSchedule = &precompile.OracleGasSchedule{GetBool: precompile.FunctionGasCost{Cold: 100, Warm: 200}}
+	assert.ErrorContains(t, other.Verify(), "invalid gas cost for getBool")
+}
+
+func TestPriceOracleDisableAndReenable(t *testing.T) {
+	stateDb := newPriceOracleTestState(t, nil)
+	config := &params.ChainConfig{
+		PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, params.DefaultOracleFeeds),
+		PrecompileUpgrades: []params.PrecompileUpgrade{
+			{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+			{PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(20), nil, params.DefaultOracleFeeds)},
+		},
+	}
+	if err := config.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	config.CheckConfigurePrecompiles(nil, big.NewInt(0), common.Big0, stateDb)
+	assert.Equal(t, []byte{0x1}, stateDb.GetCode(precompile.PriceOracleAddress))
+
+	price := streamer.Price{Price: 25000, Slot: 12001, Symbol: "AVAX/USD", Decimals: 8}
+	if err := precompile.WritePriceToState(stateDb, &price, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Disabling the oracle removes its account along with every stored price.
+	config.CheckConfigurePrecompiles(big.NewInt(5), big.NewInt(10), common.Big1, stateDb)
+	assert.False(t, stateDb.Exist(precompile.PriceOracleAddress))
+	assert.Equal(t, common.Hash{}, stateDb.GetState(precompile.PriceOracleAddress, common.Hash(precompile.AVAX_USD)))
+	assert.NotContains(t, config.AvalancheRules(common.Big0, big.NewInt(15)).Precompiles, precompile.PriceOracleAddress)
+
+	// Re-enabling the oracle starts from a clean account.
+	config.CheckConfigurePrecompiles(big.NewInt(15), big.NewInt(20), common.Big2, stateDb)
+	assert.Equal(t, []byte{0x1}, stateDb.GetCode(precompile.PriceOracleAddress))
+	assert.Equal(t, common.Hash{}, stateDb.GetState(precompile.PriceOracleAddress, common.Hash(precompile.AVAX_USD)))
+	assert.Contains(t, config.AvalancheRules(common.Big0, big.NewInt(20)).Precompiles, precompile.PriceOracleAddress)
+}
+
+func TestPriceOracleInitialFeeds(t *testing.T) {
+	admin := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	ethUsd := common.BigToHash(big.NewInt(1))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), []common.Address{admin}, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: ethUsd, Symbol: "ETH/USD", Decimals: 6, InitialPrice: &precompile.OracleInitialPrice{Price: 1_800_000_000, Slot: 42}},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	assert.Equal(t, precompile.AllowListAdmin, precompile.GetPriceOracleAllowListStatus(stateDb, admin))
+	assert.Equal(t, precompile.AllowListNoRole, precompile.GetPriceOracleAllowListStatus(stateDb, common.Address{}))
+	assert.Equal(t, uint64(2), precompile.GetFeedCount(stateDb))
+	assert.Equal(t, precompile.AVAX_USD, precompile.GetFeedIdAt(stateDb, 0))
+	assert.Equal(t, precompile.PriceFeedId(ethUsd), precompile.GetFeedIdAt(stateDb, 1))
+
+	info, ok := precompile.GetFeedInfo(stateDb, precompile.PriceFeedId(ethUsd))
+	assert.True(t, ok)
+	assert.Equal(t, &precompile.FeedInfo{Id: precompile.PriceFeedId(ethUsd), Symbol: "ETH/USD", Decimals: 6}, info)
+	id, ok := precompile.GetFeedIdBySymbol(stateDb, "ETH/USD")
+	assert.True(t, ok)
+	assert.Equal(t, precompile.PriceFeedId(ethUsd), id)
+
+	// Only feeds with an initial price are readable before the first block carrying their price.
+	initialPrice := streamer.Price{Price: 1_800_000_000, Slot: 42, Symbol: "ETH/USD", Decimals: 6}
+	assert.Equal(t, streamer.PriceToHash(&initialPrice), stateDb.GetState(precompile.PriceOracleAddress, ethUsd))
+	assert.Equal(t, common.Hash{}, stateDb.GetState(precompile.PriceOracleAddress, common.Hash(precompile.AVAX_USD)))
+
+	// Prices for unregistered symbols are rejected.
+	unknown := streamer.Price{Price: 1, Slot: 1, Symbol: "BTC/USD", Decimals: 8}
+	assert.Error(t, precompile.WritePriceToState(stateDb, &unknown, 0))
+	_, ok = precompile.GetFeedIdBySymbol(stateDb, "BTC/USD")
+	assert.False(t, ok)
+
+	// Reconfiguring does not register a feed again or overwrite its current price.
+	current := streamer.Price{Price: 1_900_000_000, Slot: 43, Symbol: "ETH/USD", Decimals: 6}
+	if err := precompile.WritePriceToState(stateDb, &current, 0); err != nil {
+		t.Fatal(err)
+	}
+	precompile.Configure(config, stateDb, common.Big0)
+	assert.Equal(t, uint64(2), precompile.GetFeedCount(stateDb))
+	assert.Equal(t, streamer.PriceToHash(&current), stateDb.GetState(precompile.PriceOracleAddress, ethUsd))
+}
+
+func TestPriceOracleLegacyFeed(t *testing.T) {
+	// Chains activated before the registry have no feeds registered.
+	stateDb := newPriceOracleTestState(t, precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil))
+	assert.Equal(t, uint64(0), precompile.GetFeedCount(stateDb))
+
+	// Other symbols do not seed the registry.
+	unknown := streamer.Price{Price: 1, Slot: 1, Symbol: "BTC/USD", Decimals: 8}
+	assert.Error(t, precompile.WritePriceToState(stateDb, &unknown, 5))
+	assert.Equal(t, uint64(0), precompile.GetFeedCount(stateDb))
+
+	// The first AVAX/USD price registers the legacy feed and is written to its slot.
+	price := streamer.Price{Price: 1_834_000_000, Slot: 10, Symbol: "AVAX/USD", Decimals: 8}
+	if err := precompile.WritePriceToState(stateDb, &price, 10); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1), precompile.GetFeedCount(stateDb))
+	info, ok := precompile.GetFeedInfo(stateDb, precompile.AVAX_USD)
+	assert.True(t, ok)
+	assert.Equal(t, &precompile.FeedInfo{Id: precompile.AVAX_USD, Symbol: "AVAX/USD", Decimals: 8}, info)
+	assert.Equal(t, streamer.PriceToHash(&price), stateDb.GetState(precompile.PriceOracleAddress, common.Hash(precompile.AVAX_USD)))
+
+	// Once seeded, the registry behaves as if the feed had been configured.
+	next := streamer.Price{Price: 1_900_000_000, Slot: 11, Symbol: "AVAX/USD", Decimals: 8}
+	if err := precompile.WritePriceToState(stateDb, &next, 11); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1), precompile.GetFeedCount(stateDb))
+	assert.Equal(t, streamer.PriceToHash(&next), stateDb.GetState(precompile.PriceOracleAddress, common.Hash(precompile.AVAX_USD)))
+}
+
+func TestPriceOracleSetPrice(t *testing.T) {
+	admin := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	publisher := common.HexToAddress("0x0000000000000000000000000000000000000def")
+	fastPublisher := common.HexToAddress("0x0000000000000000000000000000000000000fed")
+	stranger := common.HexToAddress("0x0000000000000000000000000000000000000123")
+	eurUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	navUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(4)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), []common.Address{admin}, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(eurUsd), Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed},
+		{Id: common.Hash(navUsd), Symbol: "NAV/USD", Decimals: 6, Source: precompile.FeedSourcePushed},
+	})
+	config.MinPublishInterval = 10
+	config.Publishers = []precompile.OraclePublisherConfig{{Address: fastPublisher, MinPublishInterval: 2}}
+	stateDb := newPriceOracleTestState(t, config)
+	precompile.SetPriceOracleAllowListStatus(stateDb, publisher, precompile.AllowListEnabled)
+	precompile.SetPriceOracleAllowListStatus(stateDb, fastPublisher, precompile.AllowListEnabled)
+	assert.Equal(t, uint64(10), precompile.GetPublishInterval(stateDb, publisher))
+	assert.Equal(t, uint64(2), precompile.GetPublishInterval(stateDb, fastPublisher))
+
+	blockContext := &mockBlockContext{blockNumber: big.NewInt(7), timestamp: 100}
+	accessibleState := &mockAccessibleState{state: stateDb, blockContext: blockContext}
+	contract := precompile.PriceOraclePreCompile
+	setPrice := func(caller common.Address, id precompile.PriceFeedId, price int64, expo int32, readOnly bool) error {
+		input, err := precompile.PackSetPriceInput(&id, price, expo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, remainingGas, err := contract.Run(accessibleState, caller, precompile.PriceOracleAddress, input, precompile.SetPriceGasCost, readOnly)
+		if err == nil {
+			assert.Equal(t, uint64(0), remainingGas)
+		}
+		return err
+	}
+
+	assert.NoError(t, setPrice(publisher, eurUsd, 1_080_000, -6, false))
+	expected := streamer.Price{Price: 1_080_000, Slot: 7, Symbol: "EUR/USD", Decimals: 6}
+	assert.Equal(t, streamer.PriceToHash(&expected), stateDb.GetState(precompile.PriceOracleAddress, common.Hash(eurUsd)))
+	assert.Equal(t, uint64(100), precompile.GetLastPublishTime(stateDb, publisher))
+
+	logs := stateDb.Logs()
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, precompile.PriceOracleAddress, logs[0].Address)
+		assert.Equal(t, []common.Hash{precompile.PriceUpdatedEventTopic, common.Hash(eurUsd), publisher.Hash()}, logs[0].Topics)
+		assert.Equal(t, append(common.BigToHash(big.NewInt(1_080_000)).Bytes(), math.U256Bytes(big.NewInt(-6))...), logs[0].Data)
+	}
+
+	// Header prices do not overwrite pushed feeds.
+	assert.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 1, Slot: 8, Symbol: "EUR/USD", Decimals: 6}, 100))
+	assert.Equal(t, streamer.PriceToHash(&expected), stateDb.GetState(precompile.PriceOracleAddress, common.Hash(eurUsd)))
+
+	assert.ErrorIs(t, setPrice(stranger, eurUsd, 1_090_000, -6, false), precompile.ErrCannotSetPrice)
+	assert.ErrorIs(t, setPrice(publisher, precompile.AVAX_USD, 1_090_000, -8, false), precompile.ErrFeedNotPushed)
+	assert.ErrorIs(t, setPrice(publisher, precompile.PriceFeedId(common.BigToHash(big.NewInt(3))), 1_090_000, -6, false), precompile.ErrUnknownFeed)
+	assert.ErrorIs(t, setPrice(publisher, eurUsd, 1_090_000, -8, false), precompile.ErrInvalidPriceExpo)
+	assert.ErrorIs(t, setPrice(publisher, eurUsd, 1_090_000, -6, true), vmerrs.ErrWriteProtection)
+
+	// The rate limit spans all the feeds of the publisher, so that rotating feeds does not bypass it,
+	// and the publisher can update a feed again once [MinPublishInterval] has elapsed.
+	blockContext.timestamp = 109
+	assert.ErrorIs(t, setPrice(publisher, eurUsd, 1_090_000, -6, false), precompile.ErrPublishRateLimited)
+	assert.ErrorIs(t, setPrice(publisher, navUsd, 1_000_000, -6, false), precompile.ErrPublishRateLimited)
+	blockContext.timestamp = 110
+	assert.NoError(t, setPrice(publisher, eurUsd, -1_090_000, -6, false))
+	info, ok := precompile.GetFeedInfo(stateDb, eurUsd)
+	assert.True(t, ok)
+	assert.Equal(t, precompile.FeedSourcePushed, info.Source)
+	assert.Len(t, stateDb.Logs(), 2)
+
+	// Publishers are rate limited independently, with their own interval when one is configured.
+	assert.NoError(t, setPrice(fastPublisher, navUsd, 1_000_000, -6, false))
+	blockContext.timestamp = 111
+	assert.ErrorIs(t, setPrice(fastPublisher, eurUsd, 1_091_000, -6, false), precompile.ErrPublishRateLimited)
+	blockContext.timestamp = 112
+	assert.NoError(t, setPrice(fastPublisher, eurUsd, 1_091_000, -6, false))
+}
+
+func TestPriceOracleGetPriceNoOlderThan(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	blockContext := &mockBlockContext{blockNumber: big.NewInt(3), timestamp: 130}
+	accessibleState := &mockAccessibleState{state: stateDb, blockContext: blockContext}
+	contract := precompile.PriceOraclePreCompile
+	getPrice := func(id precompile.PriceFeedId, maxAge uint64) ([]byte, error) {
+		input, err := precompile.PackGetPriceNoOlderThanInput(&id, maxAge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, _, err := contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+		return ret, err
+	}
+
+	assert.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: -2_500, Slot: 9, Symbol: "AVAX/USD", Decimals: 8}, 100))
+	assert.Equal(t, uint64(100), precompile.GetPriceUpdateTime(stateDb, precompile.AVAX_USD))
+
+	ret, err := getPrice(precompile.AVAX_USD, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, math.U256Bytes(big.NewInt(-2_500)), ret)
+
+	_, err = getPrice(precompile.AVAX_USD, 29)
+	assert.ErrorIs(t, err, precompile.ErrStalePrice)
+
+	// Run by the EVM, the error reverts the call with the StalePrice(uint256,uint64) custom error
+	// as revert data and leaves the remaining gas to the caller.
+	input, err := precompile.PackGetPriceNoOlderThanInput(&precompile.AVAX_USD, 29)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, remainingGas, err := vm.RunStatefulPrecompiledContract(contract, accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+	assert.ErrorIs(t, err, vmerrs.ErrExecutionReverted)
+	assert.Equal(t, uint64(50_000-precompile.GetPriceWarmGasCost), remainingGas)
+	expected := append(precompile.CalculateFunctionSelector("StalePrice(uint256,uint64)"), precompile.AVAX_USD.Bytes()...)
+	assert.Equal(t, append(expected, common.BigToHash(big.NewInt(100)).Bytes()...), ret)
+
+	// A registered feed that has never been written has no fresh price.
+	_, err = getPrice(ethUsd, 1_000)
+	assert.ErrorIs(t, err, precompile.ErrStalePrice)
+	_, err = getPrice(precompile.PriceFeedId(common.BigToHash(big.NewInt(2))), 1_000)
+	assert.ErrorIs(t, err, precompile.ErrUnknownFeed)
+
+	// The price becomes fresh again once a later block writes it.
+	blockContext.timestamp = 200
+	assert.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 2_600, Slot: 10, Symbol: "AVAX/USD", Decimals: 8}, 200))
+	ret, err = getPrice(precompile.AVAX_USD, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, math.U256Bytes(big.NewInt(2_600)), ret)
+}
+
+func TestPriceOracleGetPriceNoOlderThanWithDeviation(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6, DeviationBps: 50},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	blockContext := &mockBlockContext{blockNumber: big.NewInt(1), timestamp: 10}
+	accessibleState := &mockAccessibleState{state: stateDb, blockContext: blockContext}
+	getPrice := func(maxAge uint64) ([]byte, error) {
+		input, err := precompile.PackGetPriceNoOlderThanInput(&ethUsd, maxAge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, _, err := precompile.PriceOraclePreCompile.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+		return ret, err
+	}
+
+	// The feed is live but its price does not move, so only the first header price is written.
+	for timestamp := uint64(10); timestamp <= 1_000; timestamp += 10 {
+		blockContext.timestamp = timestamp
+		assert.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 1_000_000, Slot: timestamp, Symbol: "ETH/USD", Decimals: 6}, timestamp))
+	}
+	ret, err := getPrice(30)
+	assert.NoError(t, err)
+	assert.Equal(t, math.U256Bytes(big.NewInt(1_000_000)), ret)
+
+	// The price goes stale once the headers stop carrying it.
+	blockContext.timestamp = 1_100
+	_, err = getPrice(30)
+	assert.ErrorIs(t, err, precompile.ErrStalePrice)
+}
+
+func TestPriceOracleScaledPrices(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	negUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	zeroUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(3)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6},
+		{Id: common.Hash(negUsd), Symbol: "NEG/USD", Decimals: 2},
+		{Id: common.Hash(zeroUsd), Symbol: "ZERO/USD", Decimals: 2},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+	for _, price := range []*streamer.Price{
+		{Price: 1_834_000_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: 1_800_000_000, Slot: 1, Symbol: "ETH/USD", Decimals: 6},
+		{Price: -1_555, Slot: 1, Symbol: "NEG/USD", Decimals: 2},
+		{Price: 0, Slot: 1, Symbol: "ZERO/USD", Decimals: 2},
+	} {
+		if err := precompile.WritePriceToState(stateDb, price, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accessibleState := TestPrecompileAccessibleState{stateDb}
+	contract := precompile.PriceOraclePreCompile
+	run := func(input []byte, err error) (*big.Int, error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, _, err := contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+		if err != nil {
+			return nil, err
+		}
+		return math.S256(new(big.Int).SetBytes(ret)), nil
+	}
+	scaled := func(id precompile.PriceFeedId, targetDecimals uint8) (*big.Int, error) {
+		return run(precompile.PackGetPriceScaledInput(&id, targetDecimals))
+	}
+	convert := func(amount *big.Int, from, to precompile.PriceFeedId, outDecimals uint8) (*big.Int, error) {
+		return run(precompile.PackConvertInput(amount, &from, &to, outDecimals))
+	}
+
+	// Scaling rounds toward zero, for negative prices too.
+	for _, test := range []struct {
+		id             precompile.PriceFeedId
+		targetDecimals uint8
+		expected       string
+	}{
+		{precompile.AVAX_USD, 8, "1834000000"},
+		{precompile.AVAX_USD, 18, "18340000000000000000"},
+		{precompile.AVAX_USD, 0, "18"},
+		{negUsd, 1, "-155"},
+		{negUsd, 4, "-155500"},
+	} {
+		value, err := scaled(test.id, test.targetDecimals)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, value.String())
+		}
+	}
+	_, err := scaled(precompile.AVAX_USD, 255)
+	assert.ErrorIs(t, err, precompile.ErrValueOverflow)
+	_, err = scaled(precompile.PriceFeedId(common.BigToHash(big.NewInt(4))), 8)
+	assert.ErrorIs(t, err, precompile.ErrUnknownFeed)
+
+	// 1 ETH in wei is worth 1800 / 18.34 AVAX, kept in wei.
+	value, err := convert(big.NewInt(1e18), ethUsd, precompile.AVAX_USD, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "98146128680479825517", value.String())
+	}
+	// 1000 NEG is worth -15550 / 18.34 AVAX, with 2 decimals.
+	value, err = convert(big.NewInt(1_000), negUsd, precompile.AVAX_USD, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "-84787", value.String())
+	}
+	_, err = convert(big.NewInt(1_000), precompile.AVAX_USD, zeroUsd, 0)
+	assert.ErrorIs(t, err, precompile.ErrZeroPrice)
+	value, err = convert(big.NewInt(1_000), zeroUsd, precompile.AVAX_USD, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0", value.String())
+	}
+}
+
+func TestPriceOracleFeedDiscovery(t *testing.T) {
+	eurUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(50), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(eurUsd), Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed, InitialPrice: &precompile.OracleInitialPrice{Price: 1_080_000, Slot: 1}},
+	})
+	if err := config.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	// The feeds are registered by block 7, the first block at or after the upgrade timestamp.
+	stateDb := newPriceOracleTestState(t, nil)
+	precompile.Configure(config, stateDb, big.NewInt(7))
+
+	accessibleState := TestPrecompileAccessibleState{stateDb}
+	contract := precompile.PriceOraclePreCompile
+	run := func(input []byte, suppliedGas uint64) ([]byte, uint64, error) {
+		return contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, suppliedGas, true)
+	}
+
+	ret, remainingGas, err := run(precompile.PackListFeedsInput(), 50_000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50_000-precompile.ListFeedsGasCost-2*precompile.ListFeedsGasCostPerFeed), remainingGas)
+	ids, err := precompile.UnpackListFeedsOutput(ret)
+	assert.NoError(t, err)
+	assert.Equal(t, []precompile.PriceFeedId{precompile.AVAX_USD, eurUsd}, ids)
+	_, _, err = run(precompile.PackListFeedsInput(), precompile.ListFeedsGasCost+precompile.ListFeedsGasCostPerFeed)
+	assert.ErrorIs(t, err, vmerrs.ErrOutOfGas)
+
+	ret, _, err = run(precompile.PackGetFeedInfoInput(&eurUsd), 50_000)
+	assert.NoError(t, err)
+	info, status, err := precompile.UnpackGetFeedInfoOutput(eurUsd, ret)
+	assert.NoError(t, err)
+	assert.Equal(t, &precompile.FeedInfo{Id: eurUsd, Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed, ActivationBlock: 7}, info)
+	assert.Equal(t, precompile.FeedStatusActive, status)
+
+	// AVAX/USD stays pending until the first block carrying its price.
+	ret, _, err = run(precompile.PackGetFeedInfoInput(&precompile.AVAX_USD), 50_000)
+	assert.NoError(t, err)
+	_, status, err = precompile.UnpackGetFeedInfoOutput(precompile.AVAX_USD, ret)
+	assert.NoError(t, err)
+	assert.Equal(t, precompile.FeedStatusPending, status)
+
+	unknown := precompile.PriceFeedId(common.BigToHash(big.NewInt(3)))
+	_, _, err = run(precompile.PackGetFeedInfoInput(&unknown), 50_000)
+	assert.ErrorIs(t, err, precompile.ErrUnknownFeed)
+
+	ret, remainingGas, err = run(precompile.PackGetFeedIdBySymbolInput("EUR/USD"), 50_000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50_000-precompile.GetFeedIdBySymbolGasCost), remainingGas)
+	assert.Equal(t, eurUsd.Bytes(), ret)
+
+	ret, _, err = run(precompile.PackGetFeedIdBySymbolInput("BTC/USD"), 50_000)
+	assert.ErrorIs(t, err, precompile.ErrUnknownSymbol)
+	revertErr := new(precompile.RevertError)
+	if assert.ErrorAs(t, err, &revertErr) {
+		expected := precompile.CalculateFunctionSelector("UnknownSymbol(string)")
+		expected = append(expected, common.BigToHash(big.NewInt(32)).Bytes()...)
+		expected = append(expected, common.BigToHash(big.NewInt(7)).Bytes()...)
+		expected = append(expected, common.RightPadBytes([]byte("BTC/USD"), 32)...)
+		assert.Equal(t, expected, revertErr.Data())
+	}
+	assert.Nil(t, ret)
+}
+
+func TestPriceOracleUpdatePolicy(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	usdcUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(usdcUsd), Symbol: "USDC/USD", Decimals: 6},
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6, DeviationBps: 50, Heartbeat: 3_600},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	write := func(symbol string, price int64, timestamp uint64) {
+		if err := precompile.WritePriceToState(stateDb, &streamer.Price{Price: price, Slot: timestamp, Symbol: symbol, Decimals: 6}, timestamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored := func(id precompile.PriceFeedId) (int64, uint64) {
+		price, ok := precompile.ReadPriceFromState(stateDb, id)
+		if !ok {
+			t.Fatal("missing price")
+		}
+		return price.Price, precompile.GetPriceUpdateTime(stateDb, id)
+	}
+
+	// The first price is always written.
+	write("ETH/USD", 1_000_000, 10)
+	price, updated := stored(ethUsd)
+	assert.Equal(t, int64(1_000_000), price)
+	assert.Equal(t, uint64(10), updated)
+
+	// Moves of up to 0.5% are not written until the heartbeat, but still refresh the update time.
+	write("ETH/USD", 1_005_000, 20)
+	write("ETH/USD", 995_000, 30)
+	price, updated = stored(ethUsd)
+	assert.Equal(t, int64(1_000_000), price)
+	assert.Equal(t, uint64(30), updated)
+
+	write("ETH/USD", 994_999, 40)
+	price, updated = stored(ethUsd)
+	assert.Equal(t, int64(994_999), price)
+	assert.Equal(t, uint64(40), updated)
+
+	// The heartbeat counts from the last written price, not from the last refresh.
+	write("ETH/USD", 995_000, 3_639)
+	price, updated = stored(ethUsd)
+	assert.Equal(t, int64(994_999), price)
+	assert.Equal(t, uint64(3_639), updated)
+	write("ETH/USD", 995_000, 3_640)
+	price, updated = stored(ethUsd)
+	assert.Equal(t, int64(995_000), price)
+	assert.Equal(t, uint64(3_640), updated)
+
+	// Feeds without a policy are written on every block.
+	write("USDC/USD", 1_000_000, 10)
+	write("USDC/USD", 1_000_000, 11)
+	price, updated = stored(usdcUsd)
+	assert.Equal(t, int64(1_000_000), price)
+	assert.Equal(t, uint64(11), updated)
+}
+
+func TestPriceOracleHeaderPriceCache(t *testing.T) {
+	ethUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	eurUsd := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(ethUsd), Symbol: "ETH/USD", Decimals: 6, DeviationBps: 50},
+		{Id: common.Hash(eurUsd), Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	precompile.WriteHeaderPrices(stateDb, []*streamer.Price{{Price: 1_000_000, Slot: 1, Symbol: "ETH/USD", Decimals: 6}}, 10)
+	prices := precompile.WriteHeaderPrices(stateDb, []*streamer.Price{
+		{Price: 1_834_000_000, Slot: 2, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: 1_001_000, Slot: 2, Symbol: "ETH/USD", Decimals: 6},
+		{Price: 1_080_000, Slot: 2, Symbol: "EUR/USD", Decimals: 6},
+		{Price: 1, Slot: 2, Symbol: "BTC/USD", Decimals: 6},
+	}, 20)
+
+	// The cache holds the prices of the streamed feeds as stored, including those left unchanged by
+	// their update policy, but not the pushed feeds nor the unregistered symbols.
+	assert.Len(t, prices, 2)
+	for _, id := range []precompile.PriceFeedId{precompile.AVAX_USD, ethUsd} {
+		stored, ok := precompile.ReadPriceFromState(stateDb, id)
+		assert.True(t, ok)
+		assert.Equal(t, stored, prices[id])
+	}
+	assert.Equal(t, int64(1_000_000), prices[ethUsd].Price)
+
+	contract := precompile.PriceOraclePreCompile
+	run := func(cache precompile.PriceCache, input []byte) ([]byte, uint64, error) {
+		accessibleState := &mockAccessibleState{
+			state:        stateDb.Copy(),
+			blockContext: &mockBlockContext{blockNumber: big.NewInt(2), timestamp: 20, prices: cache},
+		}
+		return contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, false)
+	}
+
+	// Reads served from the cache return the same results and charge the same gas as reads from state.
+	var inputs [][]byte
+	for _, id := range []precompile.PriceFeedId{precompile.AVAX_USD, ethUsd, eurUsd, precompile.PriceFeedId(common.BigToHash(big.NewInt(3)))} {
+		id := id
+		getPrice, _ := precompile.PackGetPriceInput(&id)
+		getDecimals := append(precompile.CalculateFunctionSelector("getDecimals(uint256)"), id.Bytes()...)
+		noOlderThan, _ := precompile.PackGetPriceNoOlderThanInput(&id, 5)
+		scaled, _ := precompile.PackGetPriceScaledInput(&id, 18)
+		convert, _ := precompile.PackConvertInput(big.NewInt(1e18), &id, &precompile.AVAX_USD, 0)
+		inputs = append(inputs, getPrice, getDecimals, noOlderThan, scaled, convert)
+	}
+	for _, input := range inputs {
+		cachedRet, cachedGas, cachedErr := run(prices, input)
+		ret, remainingGas, err := run(nil, input)
+		assert.Equal(t, ret, cachedRet)
+		assert.Equal(t, remainingGas, cachedGas)
+		assert.Equal(t, err, cachedErr)
+	}
+
+	// Reads of cached feeds do not decode the price slots.
+	cached := precompile.PriceCache{precompile.AVAX_USD: {Price: 42, Slot: 3, Symbol: "AVAX/USD", Decimals: 8}}
+	input, _ := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	ret, _, err := run(cached, input)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(42)).Bytes(), ret)
+}
+
+func TestPriceOracleTypedFeeds(t *testing.T) {
+	sofr := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	reserves := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	backed := precompile.PriceFeedId(common.BigToHash(big.NewInt(3)))
+	config := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(sofr), Symbol: "SOFR", Decimals: 4, Type: precompile.FeedTypeInt256},
+		{Id: common.Hash(reserves), Symbol: "POR/ROOT", Type: precompile.FeedTypeBytes32},
+		{Id: common.Hash(backed), Symbol: "POR/OK", Type: precompile.FeedTypeBool},
+	})
+	stateDb := newPriceOracleTestState(t, config)
+
+	root := common.HexToHash("0x1234")
+	assert.NoError(t, precompile.WriteFeedValueToState(stateDb, "SOFR", common.BytesToHash(math.U256Bytes(big.NewInt(-531))), 4, 10))
+	assert.NoError(t, precompile.WriteFeedValueToState(stateDb, "POR/ROOT", root, 0, 10))
+	assert.NoError(t, precompile.WriteFeedValueToState(stateDb, "POR/OK", common.BigToHash(common.Big1), 0, 10))
+	assert.NoError(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 1_834_000_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}, 10))
+
+	// Values must match the type of their feed.
+	assert.ErrorIs(t, precompile.WriteFeedValueToState(stateDb, "POR/OK", common.BigToHash(big.NewInt(2)), 0, 10), precompile.ErrInvalidFeedValue)
+	assert.ErrorIs(t, precompile.WriteFeedValueToState(stateDb, "AVAX/USD", root, 8, 10), precompile.ErrWrongFeedType)
+	assert.ErrorIs(t, precompile.WritePriceToState(stateDb, &streamer.Price{Price: 1, Slot: 1, Symbol: "SOFR", Decimals: 4}, 10), precompile.ErrWrongFeedType)
+
+	accessibleState := TestPrecompileAccessibleState{stateDb}
+	contract := precompile.PriceOraclePreCompile
+	get := func(feedType precompile.FeedType, id precompile.PriceFeedId) ([]byte, error) {
+		input, err := precompile.PackGetFeedValueInput(feedType, &id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, remainingGas, err := contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+		if err == nil {
+			assert.Less(t, remainingGas, uint64(50_000-precompile.GetFeedValueGasCost))
+		}
+		return ret, err
+	}
+
+	ret, err := get(precompile.FeedTypeInt256, sofr)
+	if assert.NoError(t, err) {
+		value, expo, err := precompile.UnpackGetIntOutput(ret)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(-531), value)
+		assert.Equal(t, int32(-4), expo)
+	}
+	ret, err = get(precompile.FeedTypeBytes32, reserves)
+	assert.NoError(t, err)
+	assert.Equal(t, root.Bytes(), ret)
+	ret, err = get(precompile.FeedTypeBool, backed)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(common.Big1).Bytes(), ret)
+
+	// Reading a feed as another type reverts with WrongFeedType, prices included.
+	_, err = get(precompile.FeedTypeBool, sofr)
+	assert.ErrorIs(t, err, precompile.ErrWrongFeedType)
+	_, err = get(precompile.FeedTypeInt256, precompile.AVAX_USD)
+	assert.ErrorIs(t, err, precompile.ErrWrongFeedType)
+	input, err := precompile.PackGetPriceInput(&sofr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, false)
+	assert.ErrorIs(t, err, precompile.ErrWrongFeedType)
+
+	// The registry reports the type of the feed, and typed feeds become active with their first value.
+	ret, _, err = contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, precompile.PackGetFeedInfoInput(&sofr), 50_000, true)
+	if assert.NoError(t, err) {
+		info, status, err := precompile.UnpackGetFeedInfoOutput(sofr, ret)
+		assert.NoError(t, err)
+		assert.Equal(t, precompile.FeedTypeInt256, info.Type)
+		assert.Equal(t, precompile.FeedStatusActive, status)
+	}
+}
+
+func TestVerifyFeedValues(t *testing.T) {
+	sofr := precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	backed := precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+	oracleConfig := precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+		{Id: common.Hash(sofr), Symbol: "SOFR", Decimals: 4, Type: precompile.FeedTypeInt256},
+		{Id: common.Hash(backed), Symbol: "POR/OK", Type: precompile.FeedTypeBool},
+	})
+	config := *params.TestChainConfig
+	config.PriceOracleConfig = oracleConfig
+	parent := &types.Header{Number: big.NewInt(0), Time: 0}
+
+	verify := func(data []byte) error {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: 10, FeedValues: data})
+		return VerifyFeedValues(&config, block, parent, newPriceOracleTestState(t, oracleConfig))
+	}
+	encode := func(values ...*types.FeedValue) []byte {
+		data, err := types.EncodeFeedValues(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	assert.NoError(t, verify(nil))
+	assert.NoError(t, verify(encode(
+		&types.FeedValue{Symbol: "SOFR", Value: common.BytesToHash(math.U256Bytes(big.NewInt(-531))), Decimals: 4},
+		&types.FeedValue{Symbol: "POR/OK", Value: common.BigToHash(common.Big1)},
+	)))
+	assert.Error(t, verify([]byte{0xff}), "malformed values")
+	assert.Error(t, verify(encode(&types.FeedValue{Symbol: "BTC/USD", Value: common.BigToHash(common.Big1)})), "unregistered symbol")
+	assert.ErrorIs(t, verify(encode(&types.FeedValue{Symbol: "AVAX/USD", Value: common.BigToHash(common.Big1)})), precompile.ErrWrongFeedType)
+	assert.ErrorIs(t, verify(encode(&types.FeedValue{Symbol: "POR/OK", Value: common.BigToHash(common.Big2)})), precompile.ErrInvalidFeedValue)
+
+	disabled := *params.TestChainConfig
+	disabled.PriceOracleConfig = nil
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: 10, FeedValues: encode(&types.FeedValue{Symbol: "SOFR"})})
+	assert.Error(t, VerifyFeedValues(&disabled, block, parent, newPriceOracleTestState(t, nil)), "oracle disabled")
+}
+
+func TestPriceOracleGetPriceReadOnly(t *testing.T) {
+	stateDb := newPriceOracleTestState(t, nil)
+	accessibleState := TestPrecompileAccessibleState{stateDb}
+	contract := precompile.CreateNativeGetPriceerPrecompile(precompile.PriceOracleAddress)
+	input, err := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads succeed in a static call, and do not create the account of the precompile.
+	ret, _, err := contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Hash{}.Bytes(), ret)
+	assert.False(t, stateDb.Exist(precompile.PriceOracleAddress))
+
+	precompile.Configure(precompile.NewPriceOracleConfig(big.NewInt(0), nil, params.DefaultOracleFeeds), stateDb, common.Big0)
+	if err := precompile.WritePriceToState(stateDb, &streamer.Price{Price: 1_834_000_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}, 0); err != nil {
+		t.Fatal(err)
+	}
+	ret, _, err = contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, input, 50_000, true)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(1_834_000_000)).Bytes(), ret)
+
+	decimalsInput := append(precompile.CalculateFunctionSelector("getDecimals(uint256)"), precompile.AVAX_USD.Bytes()...)
+	ret, _, err = contract.Run(accessibleState, common.Address{}, precompile.PriceOracleAddress, decimalsInput, 50_000, true)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(8)).Bytes(), ret)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/ethdb"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 type ChainTest struct {
@@ -72,7 +73,14 @@ var tests = []ChainTest{
 		"InsertChainValidBlockFee",
 		TestInsertChainValidBlockFee,
 	},
-	
+	{
+		"StatefulPrecompiles",
+		TestStatefulPrecompiles,
+	},
+	{
+		"FeeConfigManager",
+		TestFeeConfigManager,
+	},
 }
 
 func copyMemDB(db ethdb.Database) (ethdb.Database, error) {
@@ -1496,142 +1504,230 @@ func TestInsertChainValidBlockFee(t *testing.T, create func(db ethdb.Database, c
 }
 
 // TestStatefulPrecompiles provides a testing framework to ensure that processing transactions interacting with the stateful precompiles work as expected.
-// func TestStatefulPrecompiles(t *testing.T, create func(db ethdb.Database, chainConfig *params.ChainConfig, lastAcceptedHash common.Hash) (*BlockChain, error)) {
-// 	var (
-// 		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
-// 		key2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
-// 		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
-// 		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
-// 		// We use two separate databases since GenerateChain commits the state roots to its underlying
-// 		// database.
-// 		genDB   = rawdb.NewMemoryDatabase()
-// 		chainDB = rawdb.NewMemoryDatabase()
-// 	)
-
-// 	// Ensure that key1 has sufficient funds in the genesis block for all of the tests.
-// 	genesisBalance := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
-// 	config := *params.TestChainConfig
-// 	// Set all of the required config parameters
-// 	config.ContractDeployerAllowListConfig = precompile.ContractDeployerAllowListConfig{
-// 		AllowListConfig: precompile.AllowListConfig{
-// 			BlockTimestamp: big.NewInt(0),
-// 			AllowListAdmins: []common.Address{
-// 				addr1,
-// 			},
-// 		},
-// 	}
-// 	gspec := &Genesis{
-// 		Config: &config,
-// 		Alloc:  GenesisAlloc{addr1: {Balance: genesisBalance}},
-// 	}
-// 	genesis := gspec.MustCommit(genDB)
-// 	_ = gspec.MustCommit(chainDB)
-
-// 	blockchain, err := create(chainDB, gspec.Config, common.Hash{})
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	defer blockchain.Stop()
-
-// 	signer := types.LatestSigner(params.TestChainConfig)
-// 	tip := big.NewInt(50000 * params.GWei)
-
-// 	// Simple framework to add a test that the stateful precompile works as expected
-// 	type test struct {
-// 		addTx         func(gen *BlockGen)
-// 		verifyGenesis func(sdb *state.StateDB)
-// 		verifyState   func(sdb *state.StateDB) error
-// 	}
-// 	tests := map[string]test{
-// 		"allow list": {
-// 			addTx: func(gen *BlockGen) {
-// 				feeCap := new(big.Int).Add(gen.BaseFee(), tip)
-// 				input, err := precompile.PackModifyAllowList(addr2, precompile.AllowListAdmin)
-// 				if err != nil {
-// 					t.Fatal(err)
-// 				}
-// 				tx := types.NewTx(&types.DynamicFeeTx{
-// 					ChainID:   params.TestChainConfig.ChainID,
-// 					Nonce:     gen.TxNonce(addr1),
-// 					To:        &precompile.ContractDeployerAllowListAddress,
-// 					Gas:       3_000_000,
-// 					Value:     common.Big0,
-// 					GasFeeCap: feeCap,
-// 					GasTipCap: tip,
-// 					Data:      input,
-// 				})
-
-// 				signedTx, err := types.SignTx(tx, signer, key1)
-// 				if err != nil {
-// 					t.Fatal(err)
-// 				}
-// 				gen.AddTx(signedTx)
-// 			},
-// 			verifyState: func(sdb *state.StateDB) error {
-// 				res := precompile.GetContractDeployerAllowListStatus(sdb, addr1)
-// 				if precompile.AllowListAdmin != res {
-// 					return fmt.Errorf("unexpected allow list status for addr1 %s, expected %s", res, precompile.AllowListAdmin)
-// 				}
-// 				res = precompile.GetContractDeployerAllowListStatus(sdb, addr2)
-// 				if precompile.AllowListAdmin != res {
-// 					return fmt.Errorf("unexpected allow list status for addr2 %s, expected %s", res, precompile.AllowListAdmin)
-// 				}
-// 				return nil
-// 			},
-// 			verifyGenesis: func(sdb *state.StateDB) {
-// 				res := precompile.GetContractDeployerAllowListStatus(sdb, addr1)
-// 				if precompile.AllowListAdmin != res {
-// 					t.Fatalf("unexpected allow list status for addr1 %s, expected %s", res, precompile.AllowListAdmin)
-// 				}
-// 				res = precompile.GetContractDeployerAllowListStatus(sdb, addr2)
-// 				if precompile.AllowListNoRole != res {
-// 					t.Fatalf("unexpected allow list status for addr2 %s, expected %s", res, precompile.AllowListNoRole)
-// 				}
-// 			},
-// 		},
-// 	}
-
-// 	// Generate chain of blocks using [genDB] instead of [chainDB] to avoid writing
-// 	// to the BlockChain's database while generating blocks.
-// 	chain, _, err := GenerateChain(gspec.Config, genesis, blockchain.engine, genDB, 1, 0, func(i int, gen *BlockGen) {
-// 		for _, test := range tests {
-// 			test.addTx(gen)
-// 		}
-// 	})
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	// Insert three blocks into the chain and accept only the first block.
-// 	if _, err := blockchain.InsertChain(chain); err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	if err := blockchain.Accept(chain[0]); err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	genesisState, err := blockchain.StateAt(blockchain.Genesis().Root())
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	for _, test := range tests {
-// 		if test.verifyGenesis == nil {
-// 			continue
-// 		}
-// 		test.verifyGenesis(genesisState)
-// 	}
-
-// 	// Run all of the necessary state verification
-// 	checkState := func(sdb *state.StateDB) error {
-// 		for _, test := range tests {
-// 			if err := test.verifyState(sdb); err != nil {
-// 				return err
-// 			}
-// 		}
-// 		return nil
-// 	}
-
-// 	// This tests that the precompiles work as expected when they are enabled
-
-// 	checkBlockChainState(t, blockchain, gspec, chainDB, create, checkState)
-// }
+func TestStatefulPrecompiles(t *testing.T, create func(db ethdb.Database, chainConfig *params.ChainConfig, lastAcceptedHash common.Hash) (*BlockChain, error)) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		// We use two separate databases since GenerateChain commits the state roots to its underlying
+		// database.
+		genDB   = rawdb.NewMemoryDatabase()
+		chainDB = rawdb.NewMemoryDatabase()
+	)
+
+	// Ensure that key1 has sufficient funds in the genesis block for all of the tests.
+	genesisBalance := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+	config := *params.TestChainConfig
+	// Set all of the required config parameters
+	config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(0), []common.Address{addr1})
+	gspec := &Genesis{
+		Config: &config,
+		Alloc:  GenesisAlloc{addr1: {Balance: genesisBalance}},
+	}
+	genesis := gspec.MustCommit(genDB)
+	_ = gspec.MustCommit(chainDB)
+
+	blockchain, err := create(chainDB, gspec.Config, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	tip := big.NewInt(50000 * params.GWei)
+
+	// Simple framework to add a test that the stateful precompile works as expected
+	type test struct {
+		addTx         func(gen *BlockGen)
+		verifyGenesis func(sdb *state.StateDB)
+		verifyState   func(sdb *state.StateDB) error
+	}
+	tests := map[string]test{
+		"allow list": {
+			addTx: func(gen *BlockGen) {
+				feeCap := new(big.Int).Add(gen.BaseFee(), tip)
+				input, err := precompile.PackModifyAllowList(addr2, precompile.AllowListAdmin)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tx := types.NewTx(&types.DynamicFeeTx{
+					ChainID:   params.TestChainConfig.ChainID,
+					Nonce:     gen.TxNonce(addr1),
+					To:        &precompile.ContractDeployerAllowListAddress,
+					Gas:       3_000_000,
+					Value:     common.Big0,
+					GasFeeCap: feeCap,
+					GasTipCap: tip,
+					Data:      input,
+				})
+
+				signedTx, err := types.SignTx(tx, signer, key1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				gen.AddTx(signedTx)
+			},
+			verifyState: func(sdb *state.StateDB) error {
+				res := precompile.GetContractDeployerAllowListStatus(sdb, addr1)
+				if precompile.AllowListAdmin != res {
+					return fmt.Errorf("unexpected allow list status for addr1 %s, expected %s", res, precompile.AllowListAdmin)
+				}
+				res = precompile.GetContractDeployerAllowListStatus(sdb, addr2)
+				if precompile.AllowListAdmin != res {
+					return fmt.Errorf("unexpected allow list status for addr2 %s, expected %s", res, precompile.AllowListAdmin)
+				}
+				return nil
+			},
+			verifyGenesis: func(sdb *state.StateDB) {
+				res := precompile.GetContractDeployerAllowListStatus(sdb, addr1)
+				if precompile.AllowListAdmin != res {
+					t.Fatalf("unexpected allow list status for addr1 %s, expected %s", res, precompile.AllowListAdmin)
+				}
+				res = precompile.GetContractDeployerAllowListStatus(sdb, addr2)
+				if precompile.AllowListNoRole != res {
+					t.Fatalf("unexpected allow list status for addr2 %s, expected %s", res, precompile.AllowListNoRole)
+				}
+			},
+		},
+	}
+
+	// Generate chain of blocks using [genDB] instead of [chainDB] to avoid writing
+	// to the BlockChain's database while generating blocks.
+	chain, _, err := GenerateChain(gspec.Config, genesis, blockchain.engine, genDB, 1, 0, func(i int, gen *BlockGen) {
+		for _, test := range tests {
+			test.addTx(gen)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Insert three blocks into the chain and accept only the first block.
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	if err := blockchain.Accept(chain[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	genesisState, err := blockchain.StateAt(blockchain.Genesis().Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if test.verifyGenesis == nil {
+			continue
+		}
+		test.verifyGenesis(genesisState)
+	}
+
+	// Run all of the necessary state verification
+	checkState := func(sdb *state.StateDB) error {
+		for _, test := range tests {
+			if err := test.verifyState(sdb); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// This tests that the precompiles work as expected when they are enabled
+
+	checkBlockChainState(t, blockchain, gspec, chainDB, create, checkState)
+}
+
+// TestFeeConfigManager tests that a fee config set through the fee manager precompile takes effect from the
+// block after the one that set it.
+func TestFeeConfigManager(t *testing.T, create func(db ethdb.Database, chainConfig *params.ChainConfig, lastAcceptedHash common.Hash) (*BlockChain, error)) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		// We use two separate databases since GenerateChain commits the state roots to its underlying
+		// database.
+		genDB   = rawdb.NewMemoryDatabase()
+		chainDB = rawdb.NewMemoryDatabase()
+	)
+
+	genesisBalance := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(params.Ether))
+	config := *params.TestChainConfig
+	config.FeeManagerConfig = precompile.NewFeeConfigManagerConfig(big.NewInt(0), []common.Address{addr1}, params.DefaultFeeConfig)
+	gspec := &Genesis{
+		Config: &config,
+		Alloc:  GenesisAlloc{addr1: {Balance: genesisBalance}},
+	}
+	genesis := gspec.MustCommit(genDB)
+	_ = gspec.MustCommit(chainDB)
+
+	blockchain, err := create(chainDB, gspec.Config, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+
+	newFeeConfig := *params.DefaultFeeConfig
+	newFeeConfig.GasLimit = big.NewInt(20_000_000)
+	newFeeConfig.MinBaseFee = big.NewInt(50_000_000_000)
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	tip := big.NewInt(50000 * params.GWei)
+
+	// Generate chain of blocks using [genDB] instead of [chainDB] to avoid writing
+	// to the BlockChain's database while generating blocks.
+	chain, _, err := GenerateChain(gspec.Config, genesis, blockchain.engine, genDB, 2, 10, func(i int, gen *BlockGen) {
+		if i != 0 {
+			return
+		}
+		input, err := precompile.PackSetFeeConfig(&newFeeConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   params.TestChainConfig.ChainID,
+			Nonce:     gen.TxNonce(addr1),
+			To:        &precompile.FeeConfigManagerAddress,
+			Gas:       3_000_000,
+			Value:     common.Big0,
+			GasFeeCap: new(big.Int).Add(gen.BaseFee(), tip),
+			GasTipCap: tip,
+			Data:      input,
+		})
+		signedTx, err := types.SignTx(tx, signer, key1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(signedTx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The block setting the fee config is still built with the initial fee config.
+	if gasLimit := chain[0].GasLimit(); gasLimit != params.DefaultFeeConfig.GasLimit.Uint64() {
+		t.Fatalf("expected gas limit of block 1 to be %d, but found %d", params.DefaultFeeConfig.GasLimit, gasLimit)
+	}
+	if gasLimit := chain[1].GasLimit(); gasLimit != newFeeConfig.GasLimit.Uint64() {
+		t.Fatalf("expected gas limit of block 2 to be %d, but found %d", newFeeConfig.GasLimit, gasLimit)
+	}
+	if baseFee := chain[1].BaseFee(); baseFee.Cmp(newFeeConfig.MinBaseFee) < 0 {
+		t.Fatalf("expected base fee of block 2 (%d) to be at least %d", baseFee, newFeeConfig.MinBaseFee)
+	}
+
+	// Inserting the chain verifies the gas fields of each block against the fee config of its parent.
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range chain {
+		if err := blockchain.Accept(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feeConfig, err := blockchain.GetFeeConfigAt(chain[1].Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newFeeConfig.Equal(feeConfig) {
+		t.Fatalf("expected fee config %v, but found %v", newFeeConfig, feeConfig)
+	}
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 const (
@@ -158,6 +159,7 @@ type blockChain interface {
 	GetBlock(hash common.Hash, number uint64) *types.Block
 	StateAt(root common.Hash) (*state.StateDB, error)
 	SenderCacher() *TxSenderCacher
+	GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error)
 
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
@@ -694,6 +696,16 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if err := pool.CheckNonceOrdering(from, tx.Nonce()); err != nil {
 		return err
 	}
+	// If the tx allow list is enabled, return an error if the from address is not allow listed.
+	headTimestamp := new(big.Int).SetUint64(pool.currentHead.Time)
+	if pool.chainconfig.IsTxAllowList(headTimestamp) {
+		pool.currentStateLock.Lock()
+		txAllowListRole := precompile.GetTxAllowListStatus(pool.currentState, from)
+		pool.currentStateLock.Unlock()
+		if !txAllowListRole.IsEnabled() {
+			return fmt.Errorf("%w: %s", precompile.ErrSenderAddressNotAllowListed, from)
+		}
+	}
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
 	pool.currentStateLock.Lock()
@@ -1253,9 +1265,18 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	if reset != nil {
 		pool.demoteUnexecutables()
 		if reset.newHead != nil && pool.chainconfig.IsSubnetEVM(new(big.Int).SetUint64(reset.newHead.Time)) {
-			_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, reset.newHead, uint64(time.Now().Unix()))
-			if err == nil {
-				pool.priced.SetBaseFee(baseFeeEstimate)
+			if feeConfig, err := pool.chain.GetFeeConfigAt(reset.newHead); err == nil {
+				// Once the fee manager is enabled or the minimum base fee is pegged to USD, the minimum fee
+				// follows the fee config read from the state of the new head.
+				if pool.minimumFee != nil && feeConfigReadsState(pool.chainconfig, reset.newHead) {
+					pool.minimumFee = feeConfig.MinBaseFee
+				}
+				_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, feeConfig, reset.newHead, uint64(time.Now().Unix()))
+				if err == nil {
+					pool.priced.SetBaseFee(baseFeeEstimate)
+				}
+			} else {
+				log.Error("failed to get fee config", "head", reset.newHead.Hash(), "err", err)
 			}
 		}
 
@@ -1696,7 +1717,12 @@ func (pool *TxPool) updateBaseFee() {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, pool.currentHead, uint64(time.Now().Unix()))
+	feeConfig, err := pool.chain.GetFeeConfigAt(pool.currentHead)
+	if err != nil {
+		log.Error("failed to get fee config", "currentHead", pool.currentHead.Hash(), "err", err)
+		return
+	}
+	_, baseFeeEstimate, err := dummy.EstimateNextBaseFee(pool.chainconfig, feeConfig, pool.currentHead, uint64(time.Now().Unix()))
 	if err == nil {
 		pool.priced.SetBaseFee(baseFeeEstimate)
 	} else {
@@ -46,6 +46,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/trie"
 )
 
@@ -91,6 +92,10 @@ func (bc *testBlockChain) reset(statedb *state.StateDB, gasLimit uint64, chainHe
 	bc.chainHeadFeed = chainHeadFeed
 }
 
+func (bc *testBlockChain) GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error) {
+	return params.DefaultFeeConfig, nil
+}
+
 func (bc *testBlockChain) CurrentBlock() *types.Block {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
@@ -351,6 +356,30 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+func TestTxAllowList(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.TxAllowListConfig = precompile.NewTxAllowListConfig(big.NewInt(0), nil)
+	pool, key := setupTxPoolWithConfig(&config)
+	defer pool.Stop()
+
+	tx := transaction(0, 100000, key)
+	from, _ := deriveSender(tx)
+	testAddBalance(pool, from, big.NewInt(0xffffffffffffff))
+
+	if err := pool.AddRemote(tx); !errors.Is(err, precompile.ErrSenderAddressNotAllowListed) {
+		t.Error("expected", precompile.ErrSenderAddressNotAllowListed, "got", err)
+	}
+
+	pool.mu.Lock()
+	precompile.SetTxAllowListStatus(pool.currentState, from, precompile.AllowListEnabled)
+	pool.mu.Unlock()
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected", nil, "got", err)
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	t.Parallel()
 
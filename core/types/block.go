@@ -101,6 +101,9 @@ type Header struct {
 	BlockGasCost *big.Int `json:"blockGasCost" rlp:"optional"`
 
 	Prices []byte `json:"blockPrices" rlp:"optional"`
+
+	// FeedValues holds the RLP encoded typed oracle values carried by the block, see [FeedValue].
+	FeedValues []byte `json:"blockFeedValues" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -378,9 +381,48 @@ func (b *Block) SetPrices(prices []*streamer.Price) {
 
 func (b *Block) GetPrices() []*streamer.Price {
 
-	prices, _ := streamer.BytesToPrices(b.header.Prices)
+	prices, _ := DecodePrices(b.header.Prices)
 
 	return prices
 }
 
+// FeedValue is a typed oracle value carried in a block header. The type the price oracle registered for
+// [Symbol] determines how [Value] is read: as a two's complement int256 with [Decimals] decimals, as an
+// opaque word or as a bool (0 or 1).
+type FeedValue struct {
+	Symbol   string
+	Value    common.Hash
+	Decimals uint16
+}
+
+// SetFeedValues sets the typed oracle values carried by b, which carries none if [values] is empty.
+func (b *Block) SetFeedValues(values []*FeedValue) {
+	b.header.FeedValues, _ = EncodeFeedValues(values)
+}
+
+// GetFeedValues returns the typed oracle values carried by b, or an error if they cannot be decoded.
+func (b *Block) GetFeedValues() ([]*FeedValue, error) {
+	return DecodeFeedValues(b.header.FeedValues)
+}
+
+// EncodeFeedValues encodes [values] as carried by a header, which carries none if [values] is empty.
+func EncodeFeedValues(values []*FeedValue) ([]byte, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return rlp.EncodeToBytes(values)
+}
+
+// DecodeFeedValues decodes the typed oracle values of a header from [data].
+func DecodeFeedValues(data []byte) ([]*FeedValue, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var values []*FeedValue
+	if err := rlp.DecodeBytes(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 type Blocks []*Block
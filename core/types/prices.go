@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+)
+
+// encodedPriceSize is the size of a price in the oracle prices of a header, as encoded by
+// streamer.MarshallPrice: [0:2] symbol length, [2:10] price, [10:18] slot, [18:20] decimals,
+// [20:32] symbol, all integers little endian.
+const (
+	encodedPriceSize  = common.HashLength
+	encodedSymbolSize = encodedPriceSize - 20
+)
+
+// DecodePrices decodes the oracle prices of a header from [data], the concatenation of the
+// encodings of the prices by streamer.PricesToBytes.
+//
+// It is used instead of streamer.BytesToPrices, which never advances past the second price.
+func DecodePrices(data []byte) ([]*streamer.Price, error) {
+	if len(data)%encodedPriceSize != 0 {
+		return nil, fmt.Errorf("invalid length for encoded prices: %d", len(data))
+	}
+	var prices []*streamer.Price
+	for offset := 0; offset < len(data); offset += encodedPriceSize {
+		encoded := data[offset : offset+encodedPriceSize]
+		symbolLen := int(binary.LittleEndian.Uint16(encoded[0:2]))
+		if symbolLen > encodedSymbolSize {
+			return nil, fmt.Errorf("invalid symbol length for price %d: %d", offset/encodedPriceSize, symbolLen)
+		}
+		prices = append(prices, &streamer.Price{
+			Price:    int64(binary.LittleEndian.Uint64(encoded[2:10])),
+			Slot:     binary.LittleEndian.Uint64(encoded[10:18]),
+			Decimals: uint(binary.LittleEndian.Uint16(encoded[18:20])),
+			Symbol:   string(encoded[20 : 20+symbolLen]),
+		})
+	}
+	return prices, nil
+}
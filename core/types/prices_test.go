@@ -0,0 +1,48 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+)
+
+func TestDecodePrices(t *testing.T) {
+	prices := []*streamer.Price{
+		{Price: 1834000000, Slot: 154823941, Symbol: "AVAX/USD", Decimals: 8},
+		{Price: -7, Slot: 2, Symbol: "ETH/USD", Decimals: 6},
+		{Price: 42, Slot: 3, Symbol: "ABCDEFGHIJKL", Decimals: 0},
+	}
+	data, err := streamer.PricesToBytes(prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodePrices(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, prices) {
+		t.Fatalf("decoded prices mismatch: got %v, want %v", decoded, prices)
+	}
+
+	header := &Header{Prices: data}
+	if got := NewBlockWithHeader(header).GetPrices(); !reflect.DeepEqual(got, prices) {
+		t.Fatalf("block prices mismatch: got %v, want %v", got, prices)
+	}
+
+	if decoded, err := DecodePrices(nil); err != nil || len(decoded) != 0 {
+		t.Fatalf("expected no prices for empty data, got %v, %v", decoded, err)
+	}
+	if _, err := DecodePrices(data[:len(data)-1]); err == nil {
+		t.Fatal("expected error for truncated prices")
+	}
+	invalid := append([]byte{}, data...)
+	invalid[encodedPriceSize] = encodedSymbolSize + 1
+	if _, err := DecodePrices(invalid); err == nil {
+		t.Fatal("expected error for invalid symbol length")
+	}
+}
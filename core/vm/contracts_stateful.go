@@ -4,8 +4,11 @@
 package vm
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
 )
 
 // wrappedPrecompiledContract implements StatefulPrecompiledContract by wrapping stateless native precompiled contracts
@@ -25,7 +28,14 @@ func (w *wrappedPrecompiledContract) Run(accessibleState precompile.PrecompileAc
 	return RunPrecompiledContract(w.p, input, suppliedGas)
 }
 
-// RunStatefulPrecompiledContract confirms runs [precompile] with the specified parameters.
-func RunStatefulPrecompiledContract(precompile precompile.StatefulPrecompiledContract, accessibleState precompile.PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	return precompile.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
+// RunStatefulPrecompiledContract confirms runs [p] with the specified parameters.
+// A [precompile.RevertError] returned by [p] reverts the call with its data as the revert data
+// and the remaining gas refunded to the caller.
+func RunStatefulPrecompiledContract(p precompile.StatefulPrecompiledContract, accessibleState precompile.PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	ret, remainingGas, err = p.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
+	var revertErr *precompile.RevertError
+	if errors.As(err, &revertErr) {
+		return revertErr.Data(), remainingGas, vmerrs.ErrExecutionReverted
+	}
+	return ret, remainingGas, err
 }
@@ -27,7 +27,7 @@
 package vm
 
 import (
-	// "fmt"
+	"fmt"
 	"math/big"
 	"sync/atomic"
 	"time"
@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gattaca-com/oracle-evm/constants"
+	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
 	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/vmerrs"
@@ -116,8 +117,23 @@ type BlockContext struct {
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
 	BaseFee     *big.Int       // Provides information for BASEFEE
+
+	// Prices caches the oracle prices written from the header at the start of the block
+	Prices precompile.PriceCache
+}
+
+// precompileBlockContext exposes the information of a BlockContext about the block
+// being processed to stateful precompiles.
+type precompileBlockContext struct {
+	ctx *BlockContext
 }
 
+func (b precompileBlockContext) Number() *big.Int              { return b.ctx.BlockNumber }
+func (b precompileBlockContext) Timestamp() *big.Int           { return b.ctx.Time }
+func (b precompileBlockContext) Coinbase() common.Address      { return b.ctx.Coinbase }
+func (b precompileBlockContext) BaseFee() *big.Int             { return b.ctx.BaseFee }
+func (b precompileBlockContext) Prices() precompile.PriceCache { return b.ctx.Prices }
+
 // TxContext provides the EVM with information about a transaction.
 // All fields can change between transactions.
 type TxContext struct {
@@ -201,6 +217,23 @@ func (evm *EVM) GetStateDB() precompile.StateDB {
 	return evm.StateDB
 }
 
+// GetBlockContext returns the evm's BlockContext
+func (evm *EVM) GetBlockContext() precompile.BlockContext {
+	return precompileBlockContext{&evm.Context}
+}
+
+// AddLog adds a log emitted by a stateful precompile at [addr] to the evm's StateDB
+func (evm *EVM) AddLog(addr common.Address, topics []common.Hash, data []byte) {
+	evm.StateDB.AddLog(&types.Log{
+		Address: addr,
+		Topics:  topics,
+		Data:    data,
+		// This is a non-consensus field, but assigned here because
+		// core/state doesn't know the current block number.
+		BlockNumber: evm.Context.BlockNumber.Uint64(),
+	})
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() *EVMInterpreter {
 	return evm.interpreter
@@ -487,12 +520,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, common.Address{}, 0, vmerrs.ErrContractAddressCollision
 	}
 	// If the allow list is enabled, check that [evm.TxContext.Origin] has permission to deploy a contract.
-	// if evm.chainRules.IsContractDeployerAllowListEnabled {
-	// 	allowListRole := precompile.GetContractDeployerAllowListStatus(evm.StateDB, evm.TxContext.Origin)
-	// 	if !allowListRole.IsEnabled() {
-	// 		return nil, common.Address{}, 0, fmt.Errorf("tx.origin %s is not authorized to deploy a contract", evm.TxContext.Origin)
-	// 	}
-	// }
+	if evm.chainRules.IsContractDeployerAllowListEnabled {
+		allowListRole := precompile.GetContractDeployerAllowListStatus(evm.StateDB, evm.TxContext.Origin)
+		if !allowListRole.IsEnabled() {
+			return nil, common.Address{}, 0, fmt.Errorf("tx.origin %s is not authorized to deploy a contract", evm.TxContext.Origin)
+		}
+	}
 
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractDeployerAllowList(t *testing.T) {
+	admin := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	deployer := common.HexToAddress("0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	noRole := common.HexToAddress("0xF60C45c607D0f41687c94C314d300f483661E13a")
+
+	config := *params.TestChainConfig
+	config.ContractDeployerAllowListConfig = precompile.NewContractDeployerAllowListConfig(big.NewInt(10), []common.Address{admin})
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	config.CheckConfigurePrecompiles(big.NewInt(0), big.NewInt(10), common.Big1, statedb)
+	precompile.SetContractDeployerAllowListStatus(statedb, deployer, precompile.AllowListEnabled)
+
+	// STOP
+	code := []byte{0x00}
+	create := func(origin common.Address, timestamp *big.Int) error {
+		vmctx := BlockContext{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: common.Big1,
+			Time:        timestamp,
+		}
+		evm := NewEVM(vmctx, TxContext{Origin: origin}, statedb, &config, Config{})
+		_, _, _, err := evm.Create(AccountRef(origin), code, 100_000, new(big.Int))
+		return err
+	}
+
+	// Before the allow list activates anyone can deploy.
+	assert.NoError(t, create(noRole, big.NewInt(5)))
+
+	assert.NoError(t, create(admin, big.NewInt(10)))
+	assert.NoError(t, create(deployer, big.NewInt(10)))
+	assert.ErrorContains(t, create(noRole, big.NewInt(10)), "is not authorized to deploy a contract")
+}
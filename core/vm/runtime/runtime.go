@@ -33,10 +33,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/core/rawdb"
 	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 // Config is a basic type specifying certain configuration flags for running
@@ -55,6 +57,15 @@ type Config struct {
 	EVMConfig   vm.Config
 	BaseFee     *big.Int
 
+	// EnablePrecompiles configures the stateful precompiles enabled by ChainConfig
+	// at Time in the state created by Execute and Create, as done in the genesis.
+	// Without a ChainConfig, the default one enables the price oracle with the
+	// default feeds.
+	EnablePrecompiles bool
+	// Prices are written to State before execution while the price oracle is
+	// enabled, as done for the prices carried in a block header.
+	Prices []*streamer.Price
+
 	State     *state.StateDB
 	GetHashFn func(n uint64) common.Hash
 }
@@ -76,6 +87,9 @@ func setDefaults(cfg *Config) {
 			MuirGlacierBlock:    new(big.Int),
 			SubnetEVMTimestamp:  new(big.Int),
 		}
+		if cfg.EnablePrecompiles {
+			cfg.ChainConfig.PriceOracleConfig = precompile.NewPriceOracleConfig(new(big.Int), nil, params.DefaultOracleFeeds)
+		}
 	}
 
 	if cfg.Difficulty == nil {
@@ -106,6 +120,29 @@ func setDefaults(cfg *Config) {
 	}
 }
 
+// newState returns an in-memory state with the stateful precompiles enabled at
+// cfg.Time configured if cfg.EnablePrecompiles is set.
+func newState(cfg *Config) *state.StateDB {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if cfg.EnablePrecompiles {
+		cfg.ChainConfig.CheckConfigurePrecompiles(nil, cfg.Time, cfg.BlockNumber, statedb)
+	}
+	return statedb
+}
+
+// writePrices writes cfg.Prices to cfg.State if the price oracle is enabled at cfg.Time.
+func writePrices(cfg *Config) error {
+	if !cfg.ChainConfig.IsPriceOracle(cfg.Time) {
+		return nil
+	}
+	for _, price := range cfg.Prices {
+		if err := precompile.WritePriceToState(cfg.State, price, cfg.Time.Uint64()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Execute executes the code using the input as call data during the execution.
 // It returns the EVM's return value, the new state and an error if it failed.
 //
@@ -118,7 +155,10 @@ func Execute(code, input []byte, cfg *Config) ([]byte, *state.StateDB, error) {
 	setDefaults(cfg)
 
 	if cfg.State == nil {
-		cfg.State, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		cfg.State = newState(cfg)
+	}
+	if err := writePrices(cfg); err != nil {
+		return nil, cfg.State, err
 	}
 	var (
 		address = common.BytesToAddress([]byte("contract"))
@@ -151,7 +191,10 @@ func Create(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
 	setDefaults(cfg)
 
 	if cfg.State == nil {
-		cfg.State, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		cfg.State = newState(cfg)
+	}
+	if err := writePrices(cfg); err != nil {
+		return nil, common.Address{}, 0, err
 	}
 	var (
 		vmenv  = NewEnv(cfg)
@@ -174,9 +217,13 @@ func Create(input []byte, cfg *Config) ([]byte, common.Address, uint64, error) {
 // EVM's return value or an error if it failed.
 //
 // Call, unlike Execute, requires a config and also requires the State field to
-// be set.
+// be set. The stateful precompiles must already be configured in State, e.g. by
+// a previous Execute with EnablePrecompiles.
 func Call(address common.Address, input []byte, cfg *Config) ([]byte, uint64, error) {
 	setDefaults(cfg)
+	if err := writePrices(cfg); err != nil {
+		return nil, 0, err
+	}
 
 	vmenv := NewEnv(cfg)
 
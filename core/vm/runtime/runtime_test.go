@@ -37,6 +37,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/consensus"
 	"github.com/gattaca-com/oracle-evm/core"
 	"github.com/gattaca-com/oracle-evm/core/rawdb"
@@ -46,6 +48,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/eth/tracers"
 	"github.com/gattaca-com/oracle-evm/eth/tracers/logger"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 
 	// force-load js tracers to trigger registration
 	_ "github.com/gattaca-com/oracle-evm/eth/tracers/js"
@@ -365,7 +368,7 @@ func benchmarkNonModifyingCode(gas uint64, code []byte, name string, tracerCode
 	cfg.State, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
 	cfg.GasLimit = gas
 	if len(tracerCode) > 0 {
-		tracer, err := tracers.New(tracerCode, new(tracers.Context))
+		tracer, err := tracers.New(tracerCode, new(tracers.Context), nil)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -865,7 +868,7 @@ func TestRuntimeJSTracer(t *testing.T) {
 			statedb.SetCode(common.HexToAddress("0xee"), calleeCode)
 			statedb.SetCode(common.HexToAddress("0xff"), depressedCode)
 
-			tracer, err := tracers.New(jsTracer, new(tracers.Context))
+			tracer, err := tracers.New(jsTracer, new(tracers.Context), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -901,7 +904,7 @@ func TestJSTracerCreateTx(t *testing.T) {
 	code := []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.RETURN)}
 
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
-	tracer, err := tracers.New(jsTracer, new(tracers.Context))
+	tracer, err := tracers.New(jsTracer, new(tracers.Context), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -954,3 +957,57 @@ func BenchmarkTracerStepVsCallFrame(b *testing.B) {
 	benchmarkNonModifyingCode(10000000, code, "tracer-step-10M", stepTracer, b)
 	benchmarkNonModifyingCode(10000000, code, "tracer-call-frame-10M", callFrameTracer, b)
 }
+
+func TestExecuteWithPrices(t *testing.T) {
+	// Calls getPrice(AVAX/USD) on the price oracle and returns the result.
+	code := []byte{
+		byte(vm.PUSH4), 0, 0, 0, 0,
+		byte(vm.PUSH1), 0xe0,
+		byte(vm.SHL),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, // retSize
+		byte(vm.PUSH1), 0, // retOffset
+		byte(vm.PUSH1), 36, // argsSize
+		byte(vm.PUSH1), 0, // argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH20),
+	}
+	copy(code[1:5], crypto.Keccak256([]byte("getPrice(uint256)"))[:4])
+	code = append(code, precompile.PriceOracleAddress.Bytes()...)
+	code = append(code,
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.POP),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	)
+	prices := []*streamer.Price{{Price: 1_234_000_000, Slot: 1, Symbol: "AVAX/USD", Decimals: 8}}
+
+	ret, statedb, err := Execute(code, nil, &Config{EnablePrecompiles: true, Prices: prices})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if price := new(big.Int).SetBytes(ret); price.Cmp(big.NewInt(1_234_000_000)) != 0 {
+		t.Error("Expected 1234000000, got", price)
+	}
+
+	// Prices are written again on calls to the same state.
+	address := common.HexToAddress("0x0a")
+	statedb.SetCode(address, code)
+	prices[0].Price = 1_500_000_000
+	ret, _, err = Call(address, nil, &Config{EnablePrecompiles: true, Prices: prices, State: statedb})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if price := new(big.Int).SetBytes(ret); price.Cmp(big.NewInt(1_500_000_000)) != 0 {
+		t.Error("Expected 1500000000, got", price)
+	}
+
+	// Without a registered feed the prices cannot be written.
+	_, _, err = Execute(code, nil, &Config{Prices: []*streamer.Price{{Price: 1, Symbol: "BTC/USD", Decimals: 8}}, EnablePrecompiles: true})
+	if err == nil || !strings.Contains(err.Error(), "BTC/USD") {
+		t.Error("Expected an error for an unregistered feed, got", err)
+	}
+}
@@ -121,6 +121,51 @@ func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64
 	return true, nil
 }
 
+// ExportPrices exports the oracle prices carried by the accepted blocks from
+// fromBlock to toBlock into a local file, as CSV or JSONL rows of block number,
+// timestamp, symbol, price, expo and slot. Only the prices of the given feed
+// symbols are exported, or all of them if feeds is empty. The range defaults
+// to the whole accepted chain.
+func (api *PrivateAdminAPI) ExportPrices(feeds []string, fromBlock *uint64, toBlock *uint64, format string, file string) (bool, error) {
+	var first, last uint64
+	if fromBlock != nil {
+		first = *fromBlock
+	}
+	last = api.eth.LastAcceptedBlock().NumberU64()
+	if toBlock != nil {
+		if *toBlock > last {
+			return false, fmt.Errorf("last block (%d) is not accepted yet, last accepted block is %d", *toBlock, last)
+		}
+		last = *toBlock
+	}
+	if format != core.PriceExportCSV && format != core.PriceExportJSONL {
+		return false, fmt.Errorf("unsupported price export format %q", format)
+	}
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vector,
+		// since the 'file' may point to arbitrary paths on the drive
+		return false, errors.New("location would overwrite an existing file")
+	}
+	// Make sure we can create the file to export into
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+	defer out.Close()
+
+	var writer io.Writer = out
+	if strings.HasSuffix(file, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	// Export the prices
+	if err := core.ExportPrices(api.eth.ChainDb(), writer, feeds, first, last, format); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
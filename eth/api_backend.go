@@ -34,6 +34,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/gattaca-com/oracle-evm/accounts"
 	"github.com/gattaca-com/oracle-evm/consensus"
 	"github.com/gattaca-com/oracle-evm/consensus/dummy"
@@ -79,12 +80,21 @@ func (b *EthAPIBackend) LastAcceptedBlock() *types.Block {
 	return b.eth.LastAcceptedBlock()
 }
 
+func (b *EthAPIBackend) BlockChain() *core.BlockChain {
+	return b.eth.BlockChain()
+}
+
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
 	if deadline, exists := ctx.Deadline(); exists && time.Until(deadline) < 0 {
 		return nil, errExpired
 	}
-	// Treat requests for the pending, latest, or accepted block
-	// identically.
+	// Serve the pending block if the miner builds one, otherwise treat
+	// requests for the pending, latest, or accepted block identically.
+	if number == rpc.PendingBlockNumber {
+		if pending, _ := b.PendingBlockAndReceipts(); pending != nil {
+			return pending.Header(), nil
+		}
+	}
 	acceptedBlock := b.eth.LastAcceptedBlock()
 	if number.IsAccepted() {
 		return acceptedBlock.Header(), nil
@@ -127,8 +137,13 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if deadline, exists := ctx.Deadline(); exists && time.Until(deadline) < 0 {
 		return nil, errExpired
 	}
-	// Treat requests for the pending, latest, or accepted block
-	// identically.
+	// Serve the pending block if the miner builds one, otherwise treat
+	// requests for the pending, latest, or accepted block identically.
+	if number == rpc.PendingBlockNumber {
+		if pending, _ := b.PendingBlockAndReceipts(); pending != nil {
+			return pending, nil
+		}
+	}
 	acceptedBlock := b.eth.LastAcceptedBlock()
 	if number.IsAccepted() {
 		return acceptedBlock, nil
@@ -178,11 +193,31 @@ func (b *EthAPIBackend) BadBlocks() []*types.Block {
 	return b.eth.blockchain.BadBlocks()
 }
 
+// PendingBlockAndReceipts returns the pending block built by the miner, which
+// carries the prices the next block would carry but no transactions, or nil if
+// the miner has no source for those prices.
 func (b *EthAPIBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
-	return nil, nil
+	block, _, err := b.eth.miner.Pending()
+	if err != nil {
+		log.Debug("Failed to build pending block", "err", err)
+		return nil, nil
+	}
+	if block == nil {
+		return nil, nil
+	}
+	return block, types.Receipts{}
 }
 
 func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	// Serve the state of the pending block if the miner builds one
+	if number == rpc.PendingBlockNumber {
+		block, stateDb, err := b.eth.miner.Pending()
+		if err != nil {
+			log.Debug("Failed to build pending block", "err", err)
+		} else if block != nil {
+			return stateDb, block.Header(), nil
+		}
+	}
 	// Request the block by its number and retrieve its state
 	header, err := b.HeaderByNumber(ctx, number)
 	if err != nil {
@@ -431,3 +466,7 @@ func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Blo
 func (b *EthAPIBackend) MinRequiredTip(ctx context.Context, header *types.Header) (*big.Int, error) {
 	return dummy.MinRequiredTip(b.ChainConfig(), header)
 }
+
+func (b *EthAPIBackend) GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error) {
+	return b.eth.blockchain.GetFeeConfigAt(parent)
+}
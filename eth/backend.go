@@ -86,6 +86,7 @@ type Ethereum struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
+	priceIndexer      *core.ChainIndexer             // Price history indexer backfilling blocks accepted before it ran
 	closeBloomHandler chan struct{}
 
 	APIBackend *EthAPIBackend
@@ -206,6 +207,8 @@ func New(
 	}
 
 	eth.bloomIndexer.Start(eth.blockchain)
+	eth.priceIndexer = core.NewPriceHistoryIndexer(chainDb, chainConfig, params.PriceHistoryBlocks, params.PriceHistoryConfirms)
+	eth.priceIndexer.Start(eth.blockchain)
 
 	config.TxPool.Journal = ""
 	eth.txPool = core.NewTxPool(config.TxPool, chainConfig, eth.blockchain)
@@ -349,6 +352,7 @@ func (s *Ethereum) Start() {
 // FIXME remove error from type if this will never return an error
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	s.priceIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
 	s.blockchain.Stop()
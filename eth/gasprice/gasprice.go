@@ -92,6 +92,7 @@ type OracleBackend interface {
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	MinRequiredTip(ctx context.Context, header *types.Header) (*big.Int, error)
 	LastAcceptedBlock() *types.Block
+	GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error)
 }
 
 // Oracle recommends gas prices based on the content of recent
@@ -204,7 +205,7 @@ func (oracle *Oracle) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
 	// We calculate the [nextBaseFee] if a block were to be produced immediately.
 	// If [nextBaseFee] is lower than the estimate from sampling, then we return it
 	// to prevent returning an incorrectly high fee when the network is quiescent.
-	nextBaseFee, err := oracle.estimateNextBaseFee(ctx)
+	nextBaseFee, minBaseFee, err := oracle.estimateNextBaseFee(ctx)
 	if err != nil {
 		log.Warn("failed to estimate next base fee", "err", err)
 		return baseFee, nil
@@ -214,7 +215,7 @@ func (oracle *Oracle) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
 		return nil, nil
 	}
 
-	baseFee = math.BigMin(baseFee, nextBaseFee)
+	baseFee = math.BigMax(math.BigMin(baseFee, nextBaseFee), minBaseFee)
 	return baseFee, nil
 }
 
@@ -223,22 +224,28 @@ func (oracle *Oracle) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
 // block, this esimtate uses the timestamp of the latest block instead.
 // If the latest block has a nil base fee, this function will return nil as the base fee
 // of the next block.
-func (oracle *Oracle) estimateNextBaseFee(ctx context.Context) (*big.Int, error) {
+// The minimum base fee of the next block is returned alongside, so that estimates sampled from
+// recent blocks can be raised to it (e.g. when the minimum base fee is pegged to USD and the price drops).
+func (oracle *Oracle) estimateNextBaseFee(ctx context.Context) (*big.Int, *big.Int, error) {
 	// Fetch the most recent block by number
 	block, err := oracle.backend.BlockByNumber(ctx, rpc.LatestBlockNumber)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// If the fetched block does not have a base fee, return nil as the base fee
 	if block.BaseFee() == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// If the block does have a baseFee, calculate the next base fee
 	// based on the current time and add it to the tip to estimate the
 	// total gas price estimate.
-	_, nextBaseFee, err := dummy.EstimateNextBaseFee(oracle.backend.ChainConfig(), block.Header(), oracle.clock.Unix())
-	return nextBaseFee, err
+	feeConfig, err := oracle.backend.GetFeeConfigAt(block.Header())
+	if err != nil {
+		return nil, nil, err
+	}
+	_, nextBaseFee, err := dummy.EstimateNextBaseFee(oracle.backend.ChainConfig(), feeConfig, block.Header(), oracle.clock.Unix())
+	return nextBaseFee, feeConfig.MinBaseFee, err
 }
 
 // SuggestPrice returns an estimated price for legacy transactions.
@@ -252,14 +259,14 @@ func (oracle *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	// We calculate the [nextBaseFee] if a block were to be produced immediately.
 	// If [nextBaseFee] is lower than the estimate from sampling, then we return it
 	// to prevent returning an incorrectly high fee when the network is quiescent.
-	nextBaseFee, err := oracle.estimateNextBaseFee(ctx)
+	nextBaseFee, minBaseFee, err := oracle.estimateNextBaseFee(ctx)
 	if err != nil {
 		log.Warn("failed to estimate next base fee", "err", err)
 	}
 	// Separately from checking the error value, check that [nextBaseFee] is non-nil
 	// before attempting to take the minimum.
 	if nextBaseFee != nil {
-		baseFee = math.BigMin(baseFee, nextBaseFee)
+		baseFee = math.BigMax(math.BigMin(baseFee, nextBaseFee), minBaseFee)
 	}
 
 	return new(big.Int).Add(tip, baseFee), nil
@@ -40,6 +40,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/rpc"
 )
 
@@ -150,6 +151,10 @@ func (b *testBackend) MinRequiredTip(ctx context.Context, header *types.Header)
 	return dummy.MinRequiredTip(b.chain.Config(), header)
 }
 
+func (b *testBackend) GetFeeConfigAt(parent *types.Header) (*params.FeeConfig, error) {
+	return b.chain.GetFeeConfigAt(parent)
+}
+
 func (b *testBackend) CurrentHeader() *types.Header {
 	return b.chain.CurrentHeader()
 }
@@ -453,3 +458,46 @@ func TestSuggestGasPricePreAP3(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEstimateBaseFeeUSDPegged(t *testing.T) {
+	config := Config{
+		Blocks:     20,
+		Percentile: 60,
+	}
+
+	// At 10 USD per AVAX, a minimum base fee of 5*10^-7 USD per gas is 50 gwei.
+	chainConfig := *params.TestChainConfig
+	chainConfig.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8, InitialPrice: &precompile.OracleInitialPrice{Price: 1_000_000_000}},
+	})
+	chainConfig.USDFeeConfig = &params.USDFeeConfig{
+		BlockTimestamp: big.NewInt(0),
+		MinBaseFee:     big.NewInt(500_000_000_000),
+	}
+	expectedMinBaseFee := big.NewInt(50 * params.GWei)
+
+	backend := newTestBackend(t, &chainConfig, 0, nil)
+	feeConfig, err := backend.GetFeeConfigAt(backend.chain.CurrentBlock().Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeConfig.MinBaseFee.Cmp(expectedMinBaseFee) != 0 {
+		t.Fatalf("Expected min base fee (%d), got (%d)", expectedMinBaseFee, feeConfig.MinBaseFee)
+	}
+
+	oracle := NewOracle(backend, config)
+	baseFee, err := oracle.EstimateBaseFee(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseFee.Cmp(expectedMinBaseFee) != 0 {
+		t.Fatalf("Expected base fee (%d), got (%d)", expectedMinBaseFee, baseFee)
+	}
+	price, err := oracle.SuggestPrice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(expectedMinBaseFee) < 0 {
+		t.Fatalf("Expected price to be at least the min base fee (%d), got (%d)", expectedMinBaseFee, price)
+	}
+}
@@ -187,6 +187,9 @@ func (eth *Ethereum) stateAtTransaction(block *types.Block, txIndex int, reexec
 	if err != nil {
 		return nil, vm.BlockContext{}, nil, err
 	}
+	// Apply the changes made at the start of the block, such as writing the header prices.
+	context := core.NewEVMBlockContext(block.Header(), eth.blockchain, nil)
+	context.Prices = core.ApplyBlockPrelude(eth.blockchain.Config(), block, parent.Header(), context, statedb, vm.Config{})
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.BlockContext{}, statedb, nil
 	}
@@ -196,7 +199,6 @@ func (eth *Ethereum) stateAtTransaction(block *types.Block, txIndex int, reexec
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		txContext := core.NewEVMTxContext(msg)
-		context := core.NewEVMBlockContext(block.Header(), eth.blockchain, nil)
 		if idx == txIndex {
 			return msg, context, statedb, nil
 		}
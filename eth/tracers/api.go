@@ -29,6 +29,7 @@ package tracers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -175,16 +176,21 @@ type TraceConfig struct {
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	// Config specific to given tracer. Note struct logger
+	// config are historically embedded in main object.
+	TracerConfig json.RawMessage
 }
 
-// TraceCallConfig is the config for traceCall API. It holds one more
-// field to override the state for tracing.
+// TraceCallConfig is the config for traceCall API. It holds two more
+// fields to override the state and the oracle prices for tracing.
 type TraceCallConfig struct {
 	*logger.Config
 	Tracer         *string
 	Timeout        *string
 	Reexec         *uint64
+	TracerConfig   json.RawMessage
 	StateOverrides *ethapi.StateOverride
+	PriceOverrides *ethapi.PriceOverrides
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -395,8 +401,11 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 			}
 			// Send the block over to the concurrent tracers (if not in the fast-forward phase)
 			txs := next.Transactions()
+			// Apply the changes made at the start of the next block to the state handed to the tracers
+			taskState := statedb.Copy()
+			core.ApplyBlockPrelude(api.backend.ChainConfig(), next, block.Header(), core.NewEVMBlockContext(next.Header(), api.chainContext(localctx), nil), taskState, vm.Config{})
 			select {
-			case tasks <- &blockTraceTask{statedb: statedb.Copy(), block: next, rootref: block.Root(), results: make([]*txTraceResult, len(txs))}:
+			case tasks <- &blockTraceTask{statedb: taskState, block: next, rootref: block.Root(), results: make([]*txTraceResult, len(txs))}:
 			case <-notifier.Closed():
 				return
 			}
@@ -515,6 +524,7 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 		vmctx              = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 		deleteEmptyObjects = chainConfig.IsEIP158(block.Number())
 	)
+	vmctx.Prices = core.ApplyBlockPrelude(chainConfig, block, parent.Header(), vmctx, statedb, vm.Config{})
 	for i, tx := range block.Transactions() {
 		var (
 			msg, _    = tx.AsMessage(signer, block.BaseFee())
@@ -598,6 +608,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	// Feed the transactions into the tracers and return
 	var failed error
 	blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	blockCtx.Prices = core.ApplyBlockPrelude(api.backend.ChainConfig(), block, parent.Header(), blockCtx, statedb, vm.Config{})
 	for i, tx := range txs {
 		// Send the trace task over for execution
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
@@ -689,6 +700,9 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 		if err := config.StateOverrides.Apply(statedb); err != nil {
 			return nil, err
 		}
+		if err := config.PriceOverrides.Apply(statedb, block.Time()); err != nil {
+			return nil, err
+		}
 	}
 	// Execute the trace
 	msg, err := args.ToMessage(api.backend.RPCGasCap(), block.BaseFee())
@@ -700,10 +714,11 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	var traceConfig *TraceConfig
 	if config != nil {
 		traceConfig = &TraceConfig{
-			Config:  config.Config,
-			Tracer:  config.Tracer,
-			Timeout: config.Timeout,
-			Reexec:  config.Reexec,
+			Config:       config.Config,
+			Tracer:       config.Tracer,
+			Timeout:      config.Timeout,
+			Reexec:       config.Reexec,
+			TracerConfig: config.TracerConfig,
 		}
 	}
 	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
@@ -730,7 +745,7 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *Contex
 				return nil, err
 			}
 		}
-		if t, err := New(*config.Tracer, txctx); err != nil {
+		if t, err := New(*config.Tracer, txctx, config.TracerConfig); err != nil {
 			return nil, err
 		} else {
 			deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
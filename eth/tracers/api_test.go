@@ -50,6 +50,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/ethdb"
 	"github.com/gattaca-com/oracle-evm/internal/ethapi"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/rpc"
 )
 
@@ -323,6 +324,65 @@ func TestTraceCall(t *testing.T) {
 	}
 }
 
+func TestTraceCallWithPriceOverrides(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(1)
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+	}}
+	api := NewAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	input, _ := precompile.PackGetPriceInput(&precompile.AVAX_USD)
+	call := ethapi.TransactionArgs{
+		From:  &accounts[0].addr,
+		To:    &precompile.PriceOracleAddress,
+		Input: (*hexutil.Bytes)(&input),
+	}
+	price := big.NewInt(1_834_000_000)
+	expo, badExpo := int32(-8), int32(-6)
+	unknownId := common.BigToHash(big.NewInt(7)).Hex()
+
+	testSuite := []struct {
+		overrides *ethapi.PriceOverrides
+		expectErr string
+	}{
+		{overrides: &ethapi.PriceOverrides{"AVAX/USD": {Price: (*hexutil.Big)(price), Expo: &expo}}},
+		{overrides: &ethapi.PriceOverrides{common.Hash(precompile.AVAX_USD).Hex(): {Price: (*hexutil.Big)(price)}}},
+		{
+			overrides: &ethapi.PriceOverrides{"AVAX/USD": {Price: (*hexutil.Big)(price), Expo: &badExpo}},
+			expectErr: "price override for AVAX/USD has expo -6, expected -8",
+		},
+		{
+			overrides: &ethapi.PriceOverrides{"BTC/USD": {Price: (*hexutil.Big)(price)}},
+			expectErr: "price override for unknown feed BTC/USD",
+		},
+		{
+			overrides: &ethapi.PriceOverrides{unknownId: {Price: (*hexutil.Big)(price)}},
+			expectErr: "price override for unknown feed " + unknownId,
+		},
+	}
+	latest := rpc.LatestBlockNumber
+	for i, testspec := range testSuite {
+		config := &TraceCallConfig{PriceOverrides: testspec.overrides}
+		result, err := api.TraceCall(context.Background(), call, rpc.BlockNumberOrHash{BlockNumber: &latest}, config)
+		if testspec.expectErr != "" {
+			if err == nil || err.Error() != testspec.expectErr {
+				t.Errorf("test %d: error mismatch, want %v, get %v", i, testspec.expectErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: expect no error, get %v", i, err)
+			continue
+		}
+		res := result.(*ethapi.ExecutionResult)
+		if want := fmt.Sprintf("%x", common.BigToHash(price)); res.Failed || res.ReturnValue != want {
+			t.Errorf("test %d: result mismatch, want %v, get %v (failed %v)", i, want, res.ReturnValue, res.Failed)
+		}
+	}
+}
+
 func TestTraceTransaction(t *testing.T) {
 	t.Parallel()
 
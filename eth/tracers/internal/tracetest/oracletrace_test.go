@@ -0,0 +1,191 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/eth/tracers"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/tests"
+)
+
+type oracleAccess struct {
+	Type     string `json:"type"`
+	Function string `json:"function"`
+	Feed     string `json:"feed"`
+	ToFeed   string `json:"toFeed"`
+	Symbol   string `json:"symbol"`
+	Price    string `json:"price"`
+	Value    string `json:"value"`
+	Decimals uint   `json:"decimals"`
+	Slot     uint64 `json:"slot"`
+	Error    string `json:"error"`
+	Stale    bool   `json:"stale"`
+	Halted   bool   `json:"halted"`
+}
+
+// oracleCallCode returns code calling the price oracle with each of [inputs].
+func oracleCallCode(inputs ...[]byte) []byte {
+	var code []byte
+	for _, input := range inputs {
+		for offset := 0; offset < len(input); offset += common.HashLength {
+			code = append(code, byte(vm.PUSH32))
+			code = append(code, common.RightPadBytes(input[offset:], common.HashLength)[:common.HashLength]...)
+			code = append(code, byte(vm.PUSH1), byte(offset), byte(vm.MSTORE))
+		}
+		// retSize, retOffset, argsSize, argsOffset, value, address, gas
+		code = append(code, byte(vm.PUSH1), 64, byte(vm.PUSH1), 0, byte(vm.PUSH1), byte(len(input)), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH20))
+		code = append(code, precompile.PriceOracleAddress.Bytes()...)
+		code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+	}
+	return code
+}
+
+func TestOracleTracer(t *testing.T) {
+	var (
+		avaxUSD = common.Hash(precompile.AVAX_USD)
+		eurUSD  = common.BigToHash(big.NewInt(1))
+		btcUSD  = common.BigToHash(big.NewInt(2))
+		sofr    = common.BigToHash(big.NewInt(3))
+		por     = common.BigToHash(big.NewInt(4))
+	)
+	config := *params.TestPreSubnetEVMConfig
+	config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+		{Id: avaxUSD, Symbol: "AVAX/USD", Decimals: 8},
+		{Id: eurUSD, Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed},
+		{Id: btcUSD, Symbol: "BTC/USD", Decimals: 8, DeviationBps: 50, Heartbeat: 600},
+		{Id: sofr, Symbol: "SOFR", Decimals: 2, Type: precompile.FeedTypeInt256},
+		{Id: por, Symbol: "POR", Type: precompile.FeedTypeBool},
+	})
+
+	feedId := func(id common.Hash) *precompile.PriceFeedId {
+		feed := precompile.PriceFeedId(id)
+		return &feed
+	}
+	mustPack := func(input []byte, err error) []byte {
+		if err != nil {
+			t.Fatalf("failed to pack input: %v", err)
+		}
+		return input
+	}
+	code := oracleCallCode(
+		mustPack(precompile.PackGetPriceInput(feedId(avaxUSD))),
+		mustPack(precompile.PackGetPriceInput(feedId(eurUSD))),
+		mustPack(precompile.PackGetPriceInput(feedId(btcUSD))),
+		mustPack(precompile.PackGetPriceScaledInput(feedId(avaxUSD), 2)),
+		mustPack(precompile.PackConvertInput(big.NewInt(2), feedId(avaxUSD), feedId(eurUSD), 6)),
+		mustPack(precompile.PackGetFeedValueInput(precompile.FeedTypeInt256, feedId(sofr))),
+		mustPack(precompile.PackGetFeedValueInput(precompile.FeedTypeBool, feedId(por))),
+	)
+
+	to := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	tx, err := types.SignNewTx(privkey, signer, &types.LegacyTx{
+		GasPrice: big.NewInt(0),
+		Gas:      1000000,
+		To:       &to,
+	})
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	origin, _ := signer.Sender(tx)
+	txContext := vm.TxContext{
+		Origin:   origin,
+		GasPrice: big.NewInt(1),
+	}
+	context := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Coinbase:    common.Address{},
+		BlockNumber: new(big.Int).SetUint64(8000000),
+		Time:        new(big.Int).SetUint64(1000),
+		Difficulty:  big.NewInt(0x30000),
+		GasLimit:    uint64(6000000),
+	}
+	alloc := core.GenesisAlloc{
+		to: core.GenesisAccount{
+			Nonce: 1,
+			Code:  code,
+		},
+		origin: core.GenesisAccount{
+			Nonce:   0,
+			Balance: big.NewInt(500000000000000),
+		},
+	}
+
+	// trace runs the transaction on a fresh state with the oracle tracer configured by [cfg].
+	trace := func(cfg json.RawMessage) []oracleAccess {
+		_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+		precompile.Configure(config.PriceOracleConfig, statedb, common.Big0)
+		// AVAX/USD is carried in the header of the block, EUR/USD was pushed long ago and BTC/USD has no price.
+		if err := precompile.WritePriceToState(statedb, &streamer.Price{Price: 1834000000, Slot: 154823941, Symbol: "AVAX/USD", Decimals: 8}, 1000); err != nil {
+			t.Fatalf("failed to write price: %v", err)
+		}
+		precompile.StorePrice(statedb, precompile.PriceFeedId(eurUSD), &streamer.Price{Price: 1080000, Slot: 10, Symbol: "EUR/USD", Decimals: 6}, 10)
+		if err := precompile.WriteFeedValueToState(statedb, "SOFR", common.BigToHash(big.NewInt(530)), 2, 990); err != nil {
+			t.Fatalf("failed to write feed value: %v", err)
+		}
+		if err := precompile.WriteFeedValueToState(statedb, "POR", common.BigToHash(common.Big1), 0, 990); err != nil {
+			t.Fatalf("failed to write feed value: %v", err)
+		}
+		tracer, err := tracers.New("oracleTracer", nil, cfg)
+		if err != nil {
+			t.Fatalf("failed to create oracle tracer: %v", err)
+		}
+		evm := vm.NewEVM(context, txContext, statedb, &config, vm.Config{Debug: true, Tracer: tracer})
+		msg, err := tx.AsMessage(signer, nil)
+		if err != nil {
+			t.Fatalf("failed to prepare transaction for tracing: %v", err)
+		}
+		st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+		if _, err = st.TransitionDb(); err != nil {
+			t.Fatalf("failed to execute transaction: %v", err)
+		}
+		res, err := tracer.GetResult()
+		if err != nil {
+			t.Fatalf("failed to retrieve trace result: %v", err)
+		}
+		var have []oracleAccess
+		if err := json.Unmarshal(res, &have); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+		return have
+	}
+
+	want := []oracleAccess{
+		{Type: "CALL", Function: "getPrice", Feed: avaxUSD.Hex(), Symbol: "AVAX/USD", Price: "1834000000", Decimals: 8, Slot: 154823941},
+		{Type: "CALL", Function: "getPrice", Feed: eurUSD.Hex(), Symbol: "EUR/USD", Price: "1080000", Decimals: 6, Slot: 10, Stale: true},
+		{Type: "CALL", Function: "getPrice", Feed: btcUSD.Hex(), Symbol: "BTC/USD", Price: "0", Decimals: 8, Stale: true, Halted: true},
+		{Type: "CALL", Function: "getPriceScaled", Feed: avaxUSD.Hex(), Symbol: "AVAX/USD", Price: "1834", Decimals: 2},
+		{Type: "CALL", Function: "convert", Feed: avaxUSD.Hex(), ToFeed: eurUSD.Hex(), Symbol: "AVAX/USD", Price: "33962962", Decimals: 6, Stale: true},
+		{Type: "CALL", Function: "getInt", Feed: sofr.Hex(), Symbol: "SOFR", Price: "530", Decimals: 2},
+		{Type: "CALL", Function: "getBool", Feed: por.Hex(), Symbol: "POR", Value: "true"},
+	}
+	if have := trace(json.RawMessage(`{"staleAge": 300}`)); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %+v, want %+v", have, want)
+	}
+
+	// Without a stale age, only the feeds with a heartbeat are checked, against their heartbeat.
+	want[1].Stale = false
+	want[4].Stale = false
+	if have := trace(nil); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %+v, want %+v", have, want)
+	}
+}
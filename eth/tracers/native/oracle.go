@@ -0,0 +1,298 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/eth/tracers"
+	"github.com/gattaca-com/oracle-evm/precompile"
+)
+
+func init() {
+	register("oracleTracer", newOracleTracer)
+}
+
+// oracleTracerConfig is the configuration of the oracle tracer.
+type oracleTracerConfig struct {
+	// StaleAge is the number of seconds after which the value of a feed is reported as stale,
+	// measured from the timestamp of the block it was last updated in. If zero, a feed is
+	// reported as stale when it was last updated more than its heartbeat before the block,
+	// and feeds without a heartbeat are never reported as stale.
+	StaleAge uint64 `json:"staleAge"`
+}
+
+// oracleAccess is a call to the price oracle precompile.
+type oracleAccess struct {
+	Type     string `json:"type"`
+	From     string `json:"from"`
+	Function string `json:"function,omitempty"`
+	Feed     string `json:"feed,omitempty"`
+	ToFeed   string `json:"toFeed,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Price    string `json:"price,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Decimals uint   `json:"decimals,omitempty"`
+	Slot     uint64 `json:"slot,omitempty"`
+	Gas      string `json:"gas"`
+	GasUsed  string `json:"gasUsed"`
+	Input    string `json:"input"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Stale    bool   `json:"stale,omitempty"`
+	Halted   bool   `json:"halted,omitempty"`
+
+	input []byte
+}
+
+// oracleTracer records every call to the price oracle precompile made by a transaction,
+// along with the feed it accessed and the value the call returned, decoded from its output.
+// Prices and int256 values are reported in price with their decimals, bytes32 and bool
+// values in value.
+//
+// Reads returning a price that is not positive are flagged as halted, and reads of a feed
+// last updated too long before the block are flagged as stale (see [oracleTracerConfig]).
+//
+// Example:
+//
+//	> debug.traceTransaction( "0x214e597e35da083692f5386141e69f47e973b2c56e7a8073b1ea08fd7571e9de", {tracer: "oracleTracer", tracerConfig: {staleAge: 300}})
+//	[
+//	  {
+//	    type: "CALL",
+//	    from: "0x8a2a8b1cd8fc4d1ac5a9d1e3b17b3e4f3dbc2a0f",
+//	    function: "getPrice",
+//	    feed: "0x0000000000000000000000000000000000000000000000000000000000000000",
+//	    symbol: "AVAX/USD",
+//	    price: "1834000000",
+//	    decimals: 8,
+//	    slot: 154823941,
+//	    gas: "0x1d4c0",
+//	    gasUsed: "0x834",
+//	    input: "0xe7572230...",
+//	    output: "0x...6d50ee80"
+//	  }
+//	]
+type oracleTracer struct {
+	env       *vm.EVM
+	config    oracleTracerConfig
+	accesses  []oracleAccess
+	callstack []*oracleAccess // Open call frames, nil for calls to other contracts
+	interrupt uint32          // Atomic flag to signal execution interruption
+	reason    error           // Textual reason for the interruption
+}
+
+// newOracleTracer returns a native go tracer which records the accesses of a tx
+// to the price oracle, and implements vm.EVMLogger.
+func newOracleTracer(cfg json.RawMessage) (tracers.Tracer, error) {
+	var config oracleTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	return &oracleTracer{config: config, accesses: make([]oracleAccess, 0)}, nil
+}
+
+// enter opens a call frame, which is recorded on exit if it calls the price oracle.
+func (t *oracleTracer) enter(typ string, from common.Address, to common.Address, input []byte, gas uint64) {
+	if to != precompile.PriceOracleAddress {
+		t.callstack = append(t.callstack, nil)
+		return
+	}
+	access := &oracleAccess{
+		Type:  typ,
+		From:  addrToHex(from),
+		Gas:   uintToHex(gas),
+		Input: bytesToHex(input),
+		input: input,
+	}
+	t.callstack = append(t.callstack, access)
+}
+
+// exit closes the innermost call frame, recording it if it called the price oracle.
+func (t *oracleTracer) exit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size == 0 {
+		return
+	}
+	access := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	if access == nil {
+		return
+	}
+	access.GasUsed = uintToHex(gasUsed)
+	if err != nil {
+		access.Error = err.Error()
+	} else {
+		access.Output = bytesToHex(output)
+	}
+	t.decode(access, output, err == nil)
+	t.accesses = append(t.accesses, *access)
+}
+
+// decode fills in the function and the feeds accessed by [access]. If the call succeeded, it
+// also fills in the value it returned, decoded from [output], or the price it set.
+func (t *oracleTracer) decode(access *oracleAccess, output []byte, success bool) {
+	name, feed := precompile.PriceOracleFunction(access.input)
+	access.Function = name
+	if name == "" {
+		return
+	}
+	args := access.input[4:]
+
+	var feeds []precompile.PriceFeedId
+	if feed != nil {
+		feeds = append(feeds, *feed)
+	}
+	if name == "convert" {
+		if _, from, to, _, err := precompile.UnpackConvertInput(args); err == nil {
+			feeds = append(feeds, *from, *to)
+			access.ToFeed = common.Hash(*to).Hex()
+		}
+	}
+	if len(feeds) == 0 {
+		return
+	}
+	access.Feed = common.Hash(feeds[0]).Hex()
+	info, registered := precompile.GetFeedInfo(t.env.StateDB, feeds[0])
+	if registered {
+		access.Symbol = info.Symbol
+	}
+	if !success {
+		return
+	}
+
+	switch name {
+	case "setPrice":
+		if _, price, expo, err := precompile.UnpackSetPriceInput(args); err == nil {
+			access.Price = strconv.FormatInt(price, 10)
+			access.Decimals = uint(-expo)
+		}
+		return
+	case "getPrice", "getPriceNoOlderThan":
+		if registered {
+			access.Decimals = uint(info.Decimals)
+		}
+		if price, ok := precompile.ReadPriceFromState(t.env.StateDB, feeds[0]); ok {
+			access.Slot = price.Slot
+		}
+		t.decodePrice(access, output)
+	case "getPriceScaled":
+		if _, targetDecimals, err := precompile.UnpackGetPriceScaledInput(args); err == nil {
+			access.Decimals = uint(targetDecimals)
+		}
+		t.decodePrice(access, output)
+	case "convert":
+		if _, _, _, outDecimals, err := precompile.UnpackConvertInput(args); err == nil {
+			access.Decimals = uint(outDecimals)
+		}
+		t.decodePrice(access, output)
+	case "getDecimals":
+		access.Decimals = uint(new(big.Int).SetBytes(output).Uint64())
+	case "getInt":
+		if value, expo, err := precompile.UnpackGetIntOutput(output); err == nil {
+			access.Price = value.String()
+			access.Decimals = uint(-expo)
+		}
+	case "getBytes32":
+		access.Value = bytesToHex(output)
+	case "getBool":
+		access.Value = strconv.FormatBool(new(big.Int).SetBytes(output).Sign() != 0)
+	default:
+		return
+	}
+	access.Stale = t.stale(feeds)
+}
+
+// decodePrice fills in the price returned as an int256 in [output], flagging [access] as
+// halted if it is not positive.
+func (t *oracleTracer) decodePrice(access *oracleAccess, output []byte) {
+	price := math.S256(new(big.Int).SetBytes(output))
+	access.Price = price.String()
+	access.Halted = price.Sign() <= 0
+}
+
+// stale returns true if any of [feeds] was last updated more than its maximum age before the
+// block being traced: the configured stale age if set, otherwise the heartbeat of the feed.
+func (t *oracleTracer) stale(feeds []precompile.PriceFeedId) bool {
+	now := t.env.Context.Time.Uint64()
+	for _, feed := range feeds {
+		maxAge := t.config.StaleAge
+		if maxAge == 0 {
+			info, ok := precompile.GetFeedInfo(t.env.StateDB, feed)
+			if !ok || info.Heartbeat == 0 {
+				continue
+			}
+			maxAge = uint64(info.Heartbeat)
+		}
+		updated := precompile.GetPriceUpdateTime(t.env.StateDB, feed)
+		if now > updated && now-updated > maxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *oracleTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	typ := "CALL"
+	if create {
+		typ = "CREATE"
+	}
+	t.enter(typ, from, to, input, gas)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *oracleTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.exit(output, gasUsed, err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *oracleTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *oracleTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *oracleTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		t.callstack = append(t.callstack, nil)
+		return
+	}
+	t.enter(typ.String(), from, to, input, gas)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *oracleTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(output, gasUsed, err)
+}
+
+// GetResult returns the json-encoded list of price oracle accesses, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *oracleTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.accesses)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *oracleTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
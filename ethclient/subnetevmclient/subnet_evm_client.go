@@ -155,6 +155,39 @@ func (ec *Client) CallContract(ctx context.Context, msg interfaces.CallMsg, bloc
 	return hex, err
 }
 
+// OverridePrice specifies the oracle price of a feed to be overridden. If set, Expo
+// must be the negated decimals of the feed. Slot defaults to the slot of the
+// current price of the feed.
+type OverridePrice struct {
+	Price *big.Int `json:"price"`
+	Expo  *int32   `json:"expo"`
+	Slot  *uint64  `json:"slot"`
+}
+
+// CallContractWithPrices executes a message call transaction like CallContract, with
+// the oracle prices of the feeds in priceOverrides, keyed by symbol or hex encoded
+// feed id, overwritten before executing the message call.
+func (ec *Client) CallContractWithPrices(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int, overrides *map[common.Address]OverrideAccount, priceOverrides map[string]OverridePrice) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := ec.c.CallContext(
+		ctx, &hex, "eth_call", toCallArg(msg),
+		ethclient.ToBlockNumArg(blockNumber), toOverrideMap(overrides), toPriceOverrideMap(priceOverrides),
+	)
+	return hex, err
+}
+
+// EstimateGasWithPrices estimates the gas needed to execute a transaction against
+// the given block, with the oracle prices of the feeds in priceOverrides
+// overwritten before executing it.
+func (ec *Client) EstimateGasWithPrices(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int, priceOverrides map[string]OverridePrice) (uint64, error) {
+	var hex hexutil.Uint64
+	err := ec.c.CallContext(
+		ctx, &hex, "eth_estimateGas", toCallArg(msg),
+		ethclient.ToBlockNumArg(blockNumber), toPriceOverrideMap(priceOverrides),
+	)
+	return uint64(hex), err
+}
+
 // GCStats retrieves the current garbage collection stats from a geth node.
 func (ec *Client) GCStats(ctx context.Context) (*debug.GCStats, error) {
 	var result debug.GCStats
@@ -217,3 +250,23 @@ func toOverrideMap(overrides *map[common.Address]OverrideAccount) interface{} {
 	}
 	return &result
 }
+
+func toPriceOverrideMap(overrides map[string]OverridePrice) interface{} {
+	if overrides == nil {
+		return nil
+	}
+	type overridePrice struct {
+		Price *hexutil.Big    `json:"price"`
+		Expo  *int32          `json:"expo,omitempty"`
+		Slot  *hexutil.Uint64 `json:"slot,omitempty"`
+	}
+	result := make(map[string]overridePrice)
+	for key, override := range overrides {
+		result[key] = overridePrice{
+			Price: (*hexutil.Big)(override.Price),
+			Expo:  override.Expo,
+			Slot:  (*hexutil.Uint64)(override.Slot),
+		}
+	}
+	return &result
+}
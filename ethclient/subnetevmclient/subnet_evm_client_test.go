@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gattaca-com/oracle-evm/interfaces"
+	"github.com/gattaca-com/oracle-evm/internal/ethapi"
+	"github.com/gattaca-com/oracle-evm/rpc"
+)
+
+// priceOverrideService records the price overrides received by eth_call and
+// eth_estimateGas.
+type priceOverrideService struct {
+	received *ethapi.PriceOverrides
+}
+
+func (s *priceOverrideService) Call(ctx context.Context, args map[string]interface{}, blockNrOrHash rpc.BlockNumberOrHash, overrides interface{}, priceOverrides *ethapi.PriceOverrides) (hexutil.Bytes, error) {
+	s.received = priceOverrides
+	return hexutil.Bytes{0x01}, nil
+}
+
+func (s *priceOverrideService) EstimateGas(ctx context.Context, args map[string]interface{}, blockNrOrHash rpc.BlockNumberOrHash, priceOverrides *ethapi.PriceOverrides) (hexutil.Uint64, error) {
+	s.received = priceOverrides
+	return 21000, nil
+}
+
+func TestPriceOverrides(t *testing.T) {
+	service := new(priceOverrideService)
+	server := rpc.NewServer(0)
+	defer server.Stop()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	client := New(rpc.DialInProc(server))
+	defer client.c.Close()
+
+	expo, slot := int32(-8), uint64(42)
+	feedId := common.BigToHash(big.NewInt(1)).Hex()
+	priceOverrides := map[string]OverridePrice{
+		"AVAX/USD": {Price: big.NewInt(1_834_000_000), Expo: &expo},
+		feedId:     {Price: big.NewInt(300_000_000_000), Slot: &slot},
+	}
+	rpcSlot := hexutil.Uint64(slot)
+	want := &ethapi.PriceOverrides{
+		"AVAX/USD": {Price: (*hexutil.Big)(big.NewInt(1_834_000_000)), Expo: &expo},
+		feedId:     {Price: (*hexutil.Big)(big.NewInt(300_000_000_000)), Slot: &rpcSlot},
+	}
+	msg := interfaces.CallMsg{From: common.Address{1}, To: &common.Address{2}}
+
+	if _, err := client.CallContractWithPrices(context.Background(), msg, nil, nil, priceOverrides); err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	if !reflect.DeepEqual(service.received, want) {
+		t.Errorf("eth_call price overrides mismatch: got %v, want %v", service.received, want)
+	}
+
+	service.received = nil
+	if _, err := client.EstimateGasWithPrices(context.Background(), msg, nil, priceOverrides); err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if !reflect.DeepEqual(service.received, want) {
+		t.Errorf("eth_estimateGas price overrides mismatch: got %v, want %v", service.received, want)
+	}
+
+	if _, err := client.CallContractWithPrices(context.Background(), msg, nil, nil, nil); err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	if service.received != nil {
+		t.Errorf("expected no price overrides, got %v", service.received)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/accounts"
 	"github.com/gattaca-com/oracle-evm/accounts/keystore"
 	"github.com/gattaca-com/oracle-evm/accounts/scwallet"
@@ -51,6 +52,7 @@ import (
 	"github.com/gattaca-com/oracle-evm/core/vm"
 	"github.com/gattaca-com/oracle-evm/eth/tracers/logger"
 	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/rpc"
 	"github.com/gattaca-com/oracle-evm/vmerrs"
 	"github.com/tyler-smith/go-bip39"
@@ -873,7 +875,77 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 	return nil
 }
 
-func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+// PriceOverride indicates the price of an oracle feed during the execution of a
+// message call. If set, expo must be the negated decimals of the feed. The slot
+// defaults to the slot of the current price of the feed.
+type PriceOverride struct {
+	Price *hexutil.Big    `json:"price"`
+	Expo  *int32          `json:"expo"`
+	Slot  *hexutil.Uint64 `json:"slot"`
+}
+
+// PriceOverrides is the collection of overridden oracle prices, keyed by the
+// symbol or the hex encoded id of the feed.
+type PriceOverrides map[string]PriceOverride
+
+// Apply writes the overridden prices of the registered feeds into the given state,
+// as if they were written by the block at the given timestamp.
+func (diff *PriceOverrides) Apply(state *state.StateDB, timestamp uint64) error {
+	if diff == nil {
+		return nil
+	}
+	for key, override := range *diff {
+		id, price, err := override.resolve(state, key)
+		if err != nil {
+			return err
+		}
+		precompile.StorePrice(state, id, price, timestamp)
+	}
+	return nil
+}
+
+// resolve returns the id of the feed registered in state for key, which is either
+// its symbol or its hex encoded id, and the overridden price of the feed.
+func (override PriceOverride) resolve(state *state.StateDB, key string) (precompile.PriceFeedId, *streamer.Price, error) {
+	id, ok := precompile.GetFeedIdBySymbol(state, key)
+	if !ok {
+		if !strings.HasPrefix(key, "0x") || len(key) != 2+2*common.HashLength {
+			return id, nil, fmt.Errorf("price override for unknown feed %s", key)
+		}
+		id = precompile.PriceFeedId(common.HexToHash(key))
+	}
+	info, ok := precompile.GetFeedInfo(state, id)
+	if !ok {
+		return id, nil, fmt.Errorf("price override for unknown feed %s", key)
+	}
+	if info.Type != precompile.FeedTypePrice {
+		return id, nil, fmt.Errorf("price override for %s feed %s", info.Type, key)
+	}
+	if override.Price == nil {
+		return id, nil, fmt.Errorf("price override for %s is missing a price", key)
+	}
+	price := override.Price.ToInt()
+	if !price.IsInt64() {
+		return id, nil, fmt.Errorf("price override for %s does not fit in 64 bits", key)
+	}
+	if override.Expo != nil && int64(*override.Expo) != -int64(info.Decimals) {
+		return id, nil, fmt.Errorf("price override for %s has expo %d, expected %d", key, *override.Expo, -int64(info.Decimals))
+	}
+	var slot uint64
+	if override.Slot != nil {
+		slot = uint64(*override.Slot)
+	} else if current, ok := precompile.ReadPriceFromState(state, id); ok {
+		slot = current.Slot
+	}
+	return id, &streamer.Price{
+		Price:    price.Int64(),
+		Slot:     slot,
+		Symbol:   info.Symbol,
+		Decimals: uint(info.Decimals),
+	}, nil
+}
+
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, priceOverrides *PriceOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -883,9 +955,10 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 	if err := overrides.Apply(state); err != nil {
 		return nil, err
 	}
-	// If the request is for the pending block, override the block timestamp, number, and estimated
-	// base fee, so that the check runs as if it were run on a newly generated block.
-	if blkNumber, isNum := blockNrOrHash.Number(); isNum && blkNumber == rpc.PendingBlockNumber {
+	// If the request is for the pending block and the backend served the accepted block instead,
+	// override the block timestamp, number, and estimated base fee, so that the check runs as if
+	// it were run on a newly generated block.
+	if blkNumber, isNum := blockNrOrHash.Number(); isNum && blkNumber == rpc.PendingBlockNumber && header.Number.Cmp(b.CurrentHeader().Number) <= 0 {
 		// Override header with a copy to ensure the original header is not modified
 		header = types.CopyHeader(header)
 		// Grab the hash of the unmodified header, so that the modified header can point to the
@@ -900,6 +973,9 @@ func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash
 		}
 		header.BaseFee = estimatedBaseFee
 	}
+	if err := priceOverrides.Apply(state, header.Time); err != nil {
+		return nil, err
+	}
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -978,12 +1054,13 @@ func (e *revertError) ErrorData() interface{} {
 
 // Call executes the given transaction on the state for the given block number.
 //
-// Additionally, the caller can specify a batch of contract for fields overriding.
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// and the prices of oracle feeds to use during the call.
 //
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, priceOverrides *PriceOverrides) (hexutil.Bytes, error) {
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, priceOverrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -994,7 +1071,7 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, bl
 	return result.Return(), result.Err
 }
 
-func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, priceOverrides *PriceOverrides, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1068,7 +1145,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, 0, gasCap)
+		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, priceOverrides, 0, gasCap)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -1114,13 +1191,14 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block, using the oracle prices
+// given by priceOverrides if specified.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, priceOverrides *PriceOverrides) (hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+	return DoEstimateGas(ctx, s.b, args, bNrOrHash, priceOverrides, s.b.RPCGasCap())
 }
 
 // ExecutionResult groups all structured logs emitted by the EVM
@@ -0,0 +1,348 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/oracle-evm/consensus"
+	"github.com/gattaca-com/oracle-evm/consensus/dummy"
+	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/rawdb"
+	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/eth/tracers/logger"
+	"github.com/gattaca-com/oracle-evm/params"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/rpc"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+
+	// loopAddr holds a contract that loops until it runs out of gas.
+	loopAddr = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	ethUSD = precompile.PriceFeedId(common.BigToHash(big.NewInt(1)))
+	eurUSD = precompile.PriceFeedId(common.BigToHash(big.NewInt(2)))
+)
+
+// testBackend implements the parts of Backend used by the tested APIs on top of
+// a chain with the AVAX/USD and ETH/USD feeds registered with the price oracle.
+type testBackend struct {
+	Backend
+
+	chain   *core.BlockChain
+	gasCap  uint64
+	timeout time.Duration
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	config := *params.TestChainConfig
+	config.PriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, append([]precompile.OracleFeedConfig{
+		{Id: common.Hash(ethUSD), Symbol: "ETH/USD", Decimals: 8, DeviationBps: 50},
+		{Id: common.Hash(eurUSD), Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed},
+	}, params.DefaultOracleFeeds...))
+	gspec := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			testAddr: {Balance: new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))},
+			loopAddr: {Code: common.FromHex("0x5b600056")}, // JUMPDEST PUSH1 0 JUMP
+		},
+	}
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{Pruning: false}, &config, dummy.NewFaker(), vm.Config{}, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	return &testBackend{chain: chain, gasCap: 25000000}
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
+func (b *testBackend) BlockChain() *core.BlockChain     { return b.chain }
+func (b *testBackend) Engine() consensus.Engine         { return b.chain.Engine() }
+func (b *testBackend) CurrentHeader() *types.Header     { return b.chain.CurrentHeader() }
+func (b *testBackend) RPCGasCap() uint64                { return b.gasCap }
+func (b *testBackend) RPCEVMTimeout() time.Duration     { return b.timeout }
+func (b *testBackend) EstimateBaseFee(context.Context) (*big.Int, error) {
+	return big.NewInt(params.TestInitialBaseFee), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	header := b.chain.CurrentHeader()
+	if number, ok := blockNrOrHash.Number(); ok && number >= 0 {
+		header = b.chain.GetHeaderByNumber(uint64(number))
+	}
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header = b.chain.GetHeaderByHash(hash)
+	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	txContext := core.NewEVMTxContext(msg)
+	context := core.NewEVMBlockContext(header, b.chain, nil)
+	return vm.NewEVM(context, txContext, state, b.chain.Config(), *vmConfig), func() error { return nil }, nil
+}
+
+func signTestTx(t *testing.T, config *params.ChainConfig, nonce uint64, to common.Address, gas uint64, data []byte) hexutil.Bytes {
+	tx := types.NewTransaction(nonce, to, common.Big0, gas, big.NewInt(params.TestInitialBaseFee*10), data)
+	signed, err := types.SignTx(tx, types.LatestSigner(config), testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestSimulateBlocks(t *testing.T) {
+	backend := newTestBackend(t)
+	api := NewPublicBlockChainAPI(backend)
+	config := backend.ChainConfig()
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	expo := int32(-8)
+	getETH, _ := precompile.PackGetPriceInput(&ethUSD)
+	getEUR, _ := precompile.PackGetPriceInput(&eurUSD)
+	blocks := []SimulatedBlock{
+		{
+			Prices: &PriceOverrides{
+				"AVAX/USD":                {Price: (*hexutil.Big)(big.NewInt(1_800_000_000)), Expo: &expo},
+				common.Hash(ethUSD).Hex(): {Price: (*hexutil.Big)(big.NewInt(300_000_000_000))},
+			},
+			Transactions: []hexutil.Bytes{signTestTx(t, config, 0, testAddr, params.TxGas, nil)},
+		},
+		{
+			Transactions: []hexutil.Bytes{signTestTx(t, config, 1, precompile.PriceOracleAddress, 100_000, getETH)},
+		},
+		// Overrides apply to pushed feeds and to moves below the deviation threshold of a feed.
+		{
+			Prices: &PriceOverrides{
+				"ETH/USD": {Price: (*hexutil.Big)(big.NewInt(300_100_000_000))},
+				"EUR/USD": {Price: (*hexutil.Big)(big.NewInt(1_080_000))},
+			},
+			Transactions: []hexutil.Bytes{
+				signTestTx(t, config, 2, precompile.PriceOracleAddress, 100_000, getETH),
+				signTestTx(t, config, 3, precompile.PriceOracleAddress, 100_000, getEUR),
+			},
+		},
+	}
+	results, err := api.SimulateBlocks(context.Background(), blocks, latest, &logger.Config{})
+	if err != nil {
+		t.Fatalf("failed to simulate blocks: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 simulated blocks, got %d", len(results))
+	}
+	for i, result := range results {
+		if number := result.Number.ToInt().Uint64(); number != uint64(i+1) {
+			t.Errorf("block %d: expected number %d, got %d", i, i+1, number)
+		}
+		if len(result.Receipts) != len(blocks[i].Transactions) || len(result.Traces) != len(blocks[i].Transactions) {
+			t.Errorf("block %d: expected %d receipts and traces, got %d and %d", i, len(blocks[i].Transactions), len(result.Receipts), len(result.Traces))
+		}
+		for j, receipt := range result.Receipts {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				t.Errorf("block %d: expected receipt %d to be successful", i, j)
+			}
+		}
+	}
+	if got, want := results[1].Traces[0].ReturnValue, common.Bytes2Hex(common.BigToHash(big.NewInt(300_000_000_000)).Bytes()); got != want {
+		t.Errorf("expected the second block to read the ETH/USD price of the first, got %s, want %s", got, want)
+	}
+	if got, want := results[2].Traces[0].ReturnValue, common.Bytes2Hex(common.BigToHash(big.NewInt(300_100_000_000)).Bytes()); got != want {
+		t.Errorf("expected the third block to read its ETH/USD price, got %s, want %s", got, want)
+	}
+	if got, want := results[2].Traces[1].ReturnValue, common.Bytes2Hex(common.BigToHash(big.NewInt(1_080_000)).Bytes()); got != want {
+		t.Errorf("expected the third block to read its EUR/USD price, got %s, want %s", got, want)
+	}
+
+	// Nothing is written to the chain.
+	if number := backend.CurrentHeader().Number.Uint64(); number != 0 {
+		t.Errorf("expected the chain to remain at genesis, got head %d", number)
+	}
+	statedb, _, _ := backend.StateAndHeaderByNumberOrHash(context.Background(), latest)
+	if _, ok := precompile.ReadPriceFromState(statedb, ethUSD); ok {
+		t.Errorf("expected no ETH/USD price in the state of the chain")
+	}
+}
+
+func TestSimulateBlocksErrors(t *testing.T) {
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	badExpo := int32(-6)
+
+	tests := map[string]struct {
+		gasCap  uint64
+		timeout time.Duration
+		blocks  func(config *params.ChainConfig) []SimulatedBlock
+		want    string
+	}{
+		"no blocks": {
+			blocks: func(*params.ChainConfig) []SimulatedBlock { return nil },
+			want:   "no blocks to simulate",
+		},
+		"expo mismatch": {
+			blocks: func(*params.ChainConfig) []SimulatedBlock {
+				return []SimulatedBlock{{Prices: &PriceOverrides{"ETH/USD": {Price: (*hexutil.Big)(common.Big1), Expo: &badExpo}}}}
+			},
+			want: "expected -8",
+		},
+		"unknown feed": {
+			blocks: func(*params.ChainConfig) []SimulatedBlock {
+				return []SimulatedBlock{{Prices: &PriceOverrides{"BTC/USD": {Price: (*hexutil.Big)(common.Big1)}}}}
+			},
+			want: "price override for unknown feed BTC/USD",
+		},
+		"gas cap": {
+			gasCap: 50_000,
+			blocks: func(config *params.ChainConfig) []SimulatedBlock {
+				return []SimulatedBlock{
+					{Transactions: []hexutil.Bytes{signTestTx(t, config, 0, testAddr, params.TxGas, nil)}},
+					{Transactions: []hexutil.Bytes{signTestTx(t, config, 1, testAddr, params.TxGas, nil), signTestTx(t, config, 2, testAddr, params.TxGas, nil)}},
+				}
+			},
+			want: "simulated block 1: transaction 1 exceeds the gas cap 50000",
+		},
+		"timeout": {
+			timeout: time.Millisecond,
+			blocks: func(config *params.ChainConfig) []SimulatedBlock {
+				return []SimulatedBlock{{Transactions: []hexutil.Bytes{signTestTx(t, config, 0, loopAddr, 8_000_000, nil)}}}
+			},
+			want: "simulation aborted (timeout = 1ms)",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := newTestBackend(t)
+			if test.gasCap != 0 {
+				backend.gasCap = test.gasCap
+			}
+			backend.timeout = test.timeout
+			api := NewPublicBlockChainAPI(backend)
+
+			_, err := api.SimulateBlocks(context.Background(), test.blocks(backend.ChainConfig()), latest, nil)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("expected error containing %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestCallPriceOverrides(t *testing.T) {
+	backend := newTestBackend(t)
+	api := NewPublicBlockChainAPI(backend)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	expo, badExpo := int32(-8), int32(-6)
+	slot := hexutil.Uint64(42)
+	unknownId := common.BigToHash(big.NewInt(7))
+	getETH, _ := precompile.PackGetPriceInput(&ethUSD)
+	// The value of 1 ETH in AVAX, which needs the prices of both feeds.
+	convert, _ := precompile.PackConvertInput(common.Big1, &ethUSD, &precompile.AVAX_USD, 0)
+
+	tests := map[string]struct {
+		input     []byte
+		overrides *PriceOverrides
+		want      *big.Int
+		wantErr   string
+	}{
+		"symbol": {
+			input:     getETH,
+			overrides: &PriceOverrides{"ETH/USD": {Price: (*hexutil.Big)(big.NewInt(300_000_000_000)), Expo: &expo}},
+			want:      big.NewInt(300_000_000_000),
+		},
+		"hex id": {
+			input:     getETH,
+			overrides: &PriceOverrides{common.Hash(ethUSD).Hex(): {Price: (*hexutil.Big)(big.NewInt(5)), Slot: &slot}},
+			want:      big.NewInt(5),
+		},
+		"multiple prices": {
+			input: convert,
+			overrides: &PriceOverrides{
+				"ETH/USD":                              {Price: (*hexutil.Big)(big.NewInt(300_000_000_000))},
+				common.Hash(precompile.AVAX_USD).Hex(): {Price: (*hexutil.Big)(big.NewInt(1_800_000_000))},
+			},
+			want: big.NewInt(166),
+		},
+		"expo mismatch": {
+			input:     getETH,
+			overrides: &PriceOverrides{"ETH/USD": {Price: (*hexutil.Big)(common.Big1), Expo: &badExpo}},
+			wantErr:   "price override for ETH/USD has expo -6, expected -8",
+		},
+		"unknown symbol": {
+			input:     getETH,
+			overrides: &PriceOverrides{"BTC/USD": {Price: (*hexutil.Big)(common.Big1)}},
+			wantErr:   "price override for unknown feed BTC/USD",
+		},
+		"unknown id": {
+			input:     getETH,
+			overrides: &PriceOverrides{unknownId.Hex(): {Price: (*hexutil.Big)(common.Big1)}},
+			wantErr:   "price override for unknown feed " + unknownId.Hex(),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := hexutil.Bytes(test.input)
+			gas := hexutil.Uint64(1_000_000)
+			args := TransactionArgs{From: &testAddr, To: &precompile.PriceOracleAddress, Input: &input, Gas: &gas}
+
+			ret, err := api.Call(context.Background(), args, latest, nil, test.overrides)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("expected call error %q, got %v", test.wantErr, err)
+				}
+				if _, err := api.EstimateGas(context.Background(), args, &latest, test.overrides); err == nil || err.Error() != test.wantErr {
+					t.Fatalf("expected estimate error %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to call: %v", err)
+			}
+			if got := new(big.Int).SetBytes(ret); got.Cmp(test.want) != 0 {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+			if _, err := api.EstimateGas(context.Background(), args, &latest, test.overrides); err != nil {
+				t.Errorf("failed to estimate gas: %v", err)
+			}
+		})
+	}
+
+	// Without an override, the staleness checked read of ETH/USD reverts since no price was ever written.
+	input, _ := precompile.PackGetPriceNoOlderThanInput(&ethUSD, 60)
+	gas := hexutil.Uint64(1_000_000)
+	args := TransactionArgs{From: &testAddr, To: &precompile.PriceOracleAddress, Input: (*hexutil.Bytes)(&input), Gas: &gas}
+	if _, err := api.EstimateGas(context.Background(), args, &latest, nil); err == nil {
+		t.Fatal("expected gas estimation to fail without a price")
+	}
+	overrides := &PriceOverrides{"ETH/USD": {Price: (*hexutil.Big)(big.NewInt(300_000_000_000))}}
+	if _, err := api.EstimateGas(context.Background(), args, &latest, overrides); err != nil {
+		t.Fatalf("failed to estimate gas with a price override: %v", err)
+	}
+
+	// Nothing is written to the chain.
+	statedb, _, _ := backend.StateAndHeaderByNumberOrHash(context.Background(), latest)
+	if _, ok := precompile.ReadPriceFromState(statedb, ethUSD); ok {
+		t.Errorf("expected no ETH/USD price in the state of the chain")
+	}
+}
@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/rpc"
+)
+
+// PublicOracleAPI provides an API to access the prices of the price oracle.
+type PublicOracleAPI struct {
+	b Backend
+}
+
+// NewPublicOracleAPI creates a new oracle API.
+func NewPublicOracleAPI(b Backend) *PublicOracleAPI {
+	return &PublicOracleAPI{b}
+}
+
+// RPCPrice is the price of an oracle feed, in the same format as PriceOverride.
+type RPCPrice struct {
+	Symbol string         `json:"symbol"`
+	Price  *hexutil.Big   `json:"price"`
+	Expo   int32          `json:"expo"`
+	Slot   hexutil.Uint64 `json:"slot"`
+}
+
+// newRPCPrice returns the RPC representation of price.
+func newRPCPrice(price *streamer.Price) *RPCPrice {
+	return &RPCPrice{
+		Symbol: price.Symbol,
+		Price:  (*hexutil.Big)(big.NewInt(price.Price)),
+		Expo:   -int32(price.Decimals),
+		Slot:   hexutil.Uint64(price.Slot),
+	}
+}
+
+// GetPendingPrices returns the prices carried by the pending block, which are
+// the prices the next block would carry if it were built now.
+func (s *PublicOracleAPI) GetPendingPrices(ctx context.Context) ([]*RPCPrice, error) {
+	block, _ := s.b.PendingBlockAndReceipts()
+	if block == nil {
+		return nil, errors.New("pending prices are not available")
+	}
+	prices := block.GetPrices()
+	result := make([]*RPCPrice, len(prices))
+	for i, price := range prices {
+		result[i] = newRPCPrice(price)
+	}
+	return result, nil
+}
+
+// RPCFeedValue is the latest value of a typed oracle feed. Int256 values are
+// returned as a two's complement word with their expo, bytes32 and bool values
+// with a zero expo.
+type RPCFeedValue struct {
+	Symbol    string              `json:"symbol"`
+	Type      precompile.FeedType `json:"type"`
+	Value     common.Hash         `json:"value"`
+	Expo      int32               `json:"expo"`
+	UpdatedAt hexutil.Uint64      `json:"updatedAt"`
+}
+
+// GetFeedValue returns the latest value of the typed feed registered for feed,
+// which is either its symbol or its hex encoded id, in the state of the given block.
+// It returns nil if no value has been written for the feed yet.
+func (s *PublicOracleAPI) GetFeedValue(ctx context.Context, feed string, blockNrOrHash rpc.BlockNumberOrHash) (*RPCFeedValue, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	id, ok := precompile.GetFeedIdBySymbol(state, feed)
+	if !ok && strings.HasPrefix(feed, "0x") && len(feed) == 2+2*common.HashLength {
+		id = precompile.PriceFeedId(common.HexToHash(feed))
+	}
+	info, ok := precompile.GetFeedInfo(state, id)
+	if !ok {
+		return nil, fmt.Errorf("unknown feed %s", feed)
+	}
+	if info.Type == precompile.FeedTypePrice {
+		return nil, fmt.Errorf("%s is a price feed", feed)
+	}
+	value, ok := precompile.ReadFeedValueFromState(state, id)
+	if !ok {
+		return nil, nil
+	}
+	return &RPCFeedValue{
+		Symbol:    info.Symbol,
+		Type:      value.Type,
+		Value:     value.Value,
+		Expo:      -int32(value.Decimals),
+		UpdatedAt: hexutil.Uint64(precompile.GetPriceUpdateTime(state, id)),
+	}, nil
+}
@@ -0,0 +1,317 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gattaca-com/oracle-evm/consensus"
+	"github.com/gattaca-com/oracle-evm/consensus/dummy"
+	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/state"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/gattaca-com/oracle-evm/core/vm"
+	"github.com/gattaca-com/oracle-evm/eth/tracers/logger"
+	"github.com/gattaca-com/oracle-evm/rpc"
+	"github.com/gattaca-com/oracle-evm/trie"
+)
+
+// maxSimulatedBlocks is the maximum number of blocks simulated by a single call
+// to SimulateBlocks.
+const maxSimulatedBlocks = 256
+
+// SimulatedBlock is a block to be simulated on top of its parent, carrying its
+// own oracle prices, timestamp and signed transactions.
+//
+// The timestamp defaults to one second after the parent, the coinbase to the
+// coinbase of the parent and the base fee to the base fee that the consensus
+// engine would assign to the block.
+type SimulatedBlock struct {
+	Timestamp    *hexutil.Uint64 `json:"timestamp"`
+	Coinbase     *common.Address `json:"coinbase"`
+	BaseFee      *hexutil.Big    `json:"baseFee"`
+	Prices       *PriceOverrides `json:"prices"`
+	Transactions []hexutil.Bytes `json:"transactions"`
+}
+
+// SimulatedBlockResult is the outcome of a simulated block. Traces are only
+// present if a trace config was given to SimulateBlocks.
+type SimulatedBlockResult struct {
+	Number    *hexutil.Big       `json:"number"`
+	Timestamp hexutil.Uint64     `json:"timestamp"`
+	BaseFee   *hexutil.Big       `json:"baseFee"`
+	GasUsed   hexutil.Uint64     `json:"gasUsed"`
+	Receipts  types.Receipts     `json:"receipts"`
+	Logs      []*types.Log       `json:"logs"`
+	Traces    []*ExecutionResult `json:"traces,omitempty"`
+}
+
+// SimulateBlocks executes the given blocks in sequence on top of the block
+// given by blockNrOrHash, each block on the state left by the previous one.
+// The blocks go through the same state processor as accepted blocks: the
+// prices of each block are written to the price oracle and fire the price
+// triggers before its transactions run. The block fee is not enforced, since
+// simulated blocks are never sealed.
+//
+// The gas of all the simulated transactions is bounded by the RPC gas cap, and
+// the simulation is aborted once the RPC EVM timeout expires.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (s *PublicBlockChainAPI) SimulateBlocks(ctx context.Context, blocks []SimulatedBlock, blockNrOrHash rpc.BlockNumberOrHash, traceConfig *logger.Config) ([]*SimulatedBlockResult, error) {
+	defer func(start time.Time) { log.Debug("Simulating blocks finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(blocks) == 0 {
+		return nil, errors.New("no blocks to simulate")
+	}
+	if len(blocks) > maxSimulatedBlocks {
+		return nil, fmt.Errorf("too many blocks to simulate: have %d, max %d", len(blocks), maxSimulatedBlocks)
+	}
+	statedb, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	var (
+		config    = s.b.ChainConfig()
+		bc        = s.b.BlockChain()
+		processor = core.NewStateProcessor(config, bc, simulationEngine{s.b.Engine()})
+	)
+	// The fee config in effect for the simulated blocks is the one of the child of
+	// the base block, since their states are never committed to the database.
+	feeConfig, err := bc.GetFeeConfigAt(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup context so it may be cancelled when the simulation has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	timeout := s.b.RPCEVMTimeout()
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// The tracer tracks the EVM running at any time, so that it can be cancelled
+	// when the context is done.
+	tracer := &simulationTracer{config: traceConfig}
+	go func() {
+		<-ctx.Done()
+		tracer.cancel()
+	}()
+
+	var (
+		gasCap  = s.b.RPCGasCap()
+		usedCap uint64
+		results = make([]*SimulatedBlockResult, 0, len(blocks))
+	)
+	for i, sim := range blocks {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("simulation aborted (timeout = %v)", timeout)
+		}
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Coinbase:   parent.Coinbase,
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			Difficulty: common.Big1,
+		}
+		if sim.Timestamp != nil {
+			if uint64(*sim.Timestamp) < parent.Time {
+				return nil, fmt.Errorf("simulated block %d has timestamp %d prior to its parent timestamp %d", i, *sim.Timestamp, parent.Time)
+			}
+			header.Time = uint64(*sim.Timestamp)
+		}
+		if sim.Coinbase != nil {
+			header.Coinbase = *sim.Coinbase
+		}
+		timestamp := new(big.Int).SetUint64(header.Time)
+		if config.IsSubnetEVM(timestamp) {
+			header.GasLimit = feeConfig.GasLimit.Uint64()
+			header.Extra, header.BaseFee, err = dummy.CalcBaseFee(config, feeConfig, parent, header.Time)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate base fee of simulated block %d: %w", i, err)
+			}
+		}
+		if sim.BaseFee != nil {
+			header.BaseFee = sim.BaseFee.ToInt()
+		}
+		// The prices are written to state as in eth_call rather than carried in the header, which
+		// would subject them to the update policy of their feeds. They must be for feeds registered
+		// before the block.
+		if err := sim.Prices.Apply(statedb, header.Time); err != nil {
+			return nil, fmt.Errorf("simulated block %d: %w", i, err)
+		}
+		txs := make(types.Transactions, len(sim.Transactions))
+		for j, input := range sim.Transactions {
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(input); err != nil {
+				return nil, fmt.Errorf("simulated block %d: invalid transaction %d: %w", i, j, err)
+			}
+			if gasCap != 0 && tx.Gas() > gasCap-usedCap {
+				return nil, fmt.Errorf("simulated block %d: transaction %d exceeds the gas cap %d of the simulation", i, j, gasCap)
+			}
+			usedCap += tx.Gas()
+			txs[j] = tx
+		}
+		block := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+
+		vmConfig := *bc.GetVMConfig()
+		vmConfig.Debug = true
+		vmConfig.Tracer = tracer
+		tracer.reset()
+		receipts, logs, usedGas, err := processor.Process(block, parent, statedb, vmConfig)
+		// If the timer caused an abort, return an appropriate error message
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("simulation aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("simulated block %d: %w", i, err)
+		}
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		result := &SimulatedBlockResult{
+			Number:    (*hexutil.Big)(block.Number()),
+			Timestamp: hexutil.Uint64(block.Time()),
+			BaseFee:   (*hexutil.Big)(block.BaseFee()),
+			GasUsed:   hexutil.Uint64(usedGas),
+			Receipts:  receipts,
+			Logs:      logs,
+		}
+		if traceConfig != nil {
+			result.Traces = tracer.results(receipts)
+		}
+		results = append(results, result)
+
+		// Complete the header so that it can be the parent of the next simulated block.
+		header = block.Header()
+		header.GasUsed = usedGas
+		header.Root = statedb.IntermediateRoot(config.IsEIP158(header.Number))
+		header.ReceiptHash = types.DeriveSha(receipts, trie.NewStackTrie(nil))
+		header.Bloom = types.CreateBloom(receipts)
+		parent = header
+	}
+	return results, nil
+}
+
+// simulationEngine wraps the consensus engine of the chain to skip the block fee
+// checks when finalizing a simulated block.
+type simulationEngine struct {
+	consensus.Engine
+}
+
+// Finalize implements consensus.Engine, accepting any simulated block.
+func (simulationEngine) Finalize(chain consensus.ChainHeaderReader, block *types.Block, parent *types.Header, state *state.StateDB, receipts []*types.Receipt) error {
+	return nil
+}
+
+// simulationTracer tracks the EVM of every top-level call made while processing
+// the simulated blocks, so that the simulation can be cancelled. If a trace config
+// is given, it also collects their struct logs, with a new StructLogger for each
+// of them.
+type simulationTracer struct {
+	config  *logger.Config
+	loggers []*logger.StructLogger
+
+	mu        sync.Mutex
+	env       *vm.EVM // EVM of the current top-level call
+	cancelled bool    // whether the simulation was cancelled
+}
+
+// cancel aborts the current top-level call and any call started after it.
+func (t *simulationTracer) cancel() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelled = true
+	if t.env != nil {
+		t.env.Cancel()
+	}
+}
+
+// reset drops the struct logs collected for the previous simulated block.
+func (t *simulationTracer) reset() {
+	t.loggers = nil
+}
+
+func (t *simulationTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.mu.Lock()
+	t.env = env
+	if t.cancelled {
+		env.Cancel()
+	}
+	t.mu.Unlock()
+
+	if t.config == nil {
+		return
+	}
+	t.loggers = append(t.loggers, logger.NewStructLogger(t.config))
+	t.current().CaptureStart(env, from, to, create, input, gas, value)
+}
+
+func (t *simulationTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.config != nil {
+		t.current().CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *simulationTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.config != nil {
+		t.current().CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *simulationTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.config != nil {
+		t.current().CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *simulationTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.config != nil {
+		t.current().CaptureFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *simulationTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	if t.config != nil {
+		t.current().CaptureEnd(output, gasUsed, d, err)
+	}
+}
+
+func (t *simulationTracer) current() *logger.StructLogger {
+	return t.loggers[len(t.loggers)-1]
+}
+
+// results returns the traces of the transactions of the block with the given
+// receipts. The callbacks of the price triggers run before any transaction of
+// the block, so the transactions are traced by the last loggers.
+func (t *simulationTracer) results(receipts types.Receipts) []*ExecutionResult {
+	loggers := t.loggers
+	if len(loggers) > len(receipts) {
+		loggers = loggers[len(loggers)-len(receipts):]
+	}
+	results := make([]*ExecutionResult, len(loggers))
+	for i, l := range loggers {
+		results[i] = &ExecutionResult{
+			Gas:         receipts[i].GasUsed,
+			Failed:      receipts[i].Status == types.ReceiptStatusFailed,
+			ReturnValue: fmt.Sprintf("%x", l.Output()),
+			StructLogs:  FormatLogs(l.StructLogs()),
+		}
+	}
+	return results
+}
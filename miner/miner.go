@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/gattaca-com/oracle-evm/consensus"
 	"github.com/gattaca-com/oracle-evm/core"
+	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
 )
@@ -66,6 +67,24 @@ func (miner *Miner) GenerateBlock(oraclePrices []byte) (*types.Block, error) {
 	return miner.worker.commitNewWork(oraclePrices)
 }
 
+// SetPriceSource sets the source of the oracle prices the next block would carry,
+// which are applied to the pending block.
+func (miner *Miner) SetPriceSource(source func() ([]byte, error)) {
+	miner.worker.setPriceSource(source)
+}
+
+// SetFeedValueSource sets the source of the typed oracle values the next block
+// carries, which are applied to the generated and pending blocks.
+func (miner *Miner) SetFeedValueSource(source func() ([]*types.FeedValue, error)) {
+	miner.worker.setFeedValueSource(source)
+}
+
+// Pending returns the pending block, carrying the prices of the price source but
+// no transactions, and its state. It returns nil if no price source is set.
+func (miner *Miner) Pending() (*types.Block, *state.StateDB, error) {
+	return miner.worker.pending()
+}
+
 // SubscribePendingLogs starts delivering logs from pending transactions
 // to the given channel.
 func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscription {
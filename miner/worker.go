@@ -30,6 +30,7 @@
 package miner
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -40,14 +41,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/gattaca-com/OraclePriceStreamer/streamer"
 	"github.com/gattaca-com/oracle-evm/consensus"
 	"github.com/gattaca-com/oracle-evm/consensus/dummy"
 	"github.com/gattaca-com/oracle-evm/core"
 	"github.com/gattaca-com/oracle-evm/core/state"
 	"github.com/gattaca-com/oracle-evm/core/types"
 	"github.com/gattaca-com/oracle-evm/params"
-	"github.com/gattaca-com/oracle-evm/precompile"
 )
 
 // environment is the worker's current environment and holds all of the current state information.
@@ -81,9 +80,20 @@ type worker struct {
 
 	// Subscriptions
 	mux      *event.TypeMux // TODO replace
-	mu       sync.RWMutex   // The lock used to protect the coinbase and extra fields
+	mu       sync.RWMutex   // The lock used to protect the coinbase, oracle sources and extra fields
 	coinbase common.Address
 	clock    *mockable.Clock // Allows us mock the clock for testing
+
+	// priceSource returns the oracle prices the next block would carry, used to build the pending block
+	priceSource func() ([]byte, error)
+	// feedValueSource returns the typed oracle values the next block carries
+	feedValueSource func() ([]*types.FeedValue, error)
+
+	// pendingMu protects the pending block and its state, which are reused until the head, the
+	// timestamp or the oracle data they were built from change
+	pendingMu    sync.Mutex
+	pendingBlock *types.Block
+	pendingState *state.StateDB
 }
 
 func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, clock *mockable.Clock) *worker {
@@ -109,12 +119,68 @@ func (w *worker) setEtherbase(addr common.Address) {
 
 // commitNewWork generates several new sealing tasks based on the parent block.
 func (w *worker) commitNewWork(oraclePrices []byte) (*types.Block, error) {
+	// The typed oracle values are fetched before taking [w.mu], as the source may be slow.
+	_, feedValueSource := w.oracleSources()
+	feedValues, err := fetchFeedValues(feedValueSource)
+	if err != nil {
+		log.Warn("Building block without typed oracle values", "err", err)
+	}
+
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
 	tstart := w.clock.Time()
-	timestamp := tstart.Unix()
 	parent := w.chain.CurrentBlock()
+	if w.coinbase == (common.Address{}) {
+		return nil, errors.New("cannot mine without etherbase")
+	}
+	header, err := w.prepareHeader(parent, oraclePrices, feedValues, tstart)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := w.createCurrentEnvironment(parent, header, tstart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new current environment: %w", err)
+	}
+	// Validators reject blocks carrying typed values that are not valid for the registered feeds.
+	if err := core.VerifyFeedValues(w.chainConfig, types.NewBlockWithHeader(header), parent.Header(), env.state.Copy()); err != nil {
+		log.Warn("Building block without typed oracle values", "err", err)
+		header.FeedValues = nil
+	}
+	// Apply the changes made at the start of the block, as done when it is processed.
+	blockContext := core.NewEVMBlockContext(header, w.chain, &w.coinbase)
+	core.ApplyBlockPrelude(w.chainConfig, types.NewBlockWithHeader(header), parent.Header(), blockContext, env.state, *w.chain.GetVMConfig())
+
+	// Fill the block with all available pending transactions.
+	pending := w.eth.TxPool().Pending(true)
+
+	// Split the pending transactions into locals and remotes
+	localTxs := make(map[common.Address]types.Transactions)
+	remoteTxs := pending
+	for _, account := range w.eth.TxPool().Locals() {
+		if txs := remoteTxs[account]; len(txs) > 0 {
+			delete(remoteTxs, account)
+			localTxs[account] = txs
+		}
+	}
+
+	if len(localTxs) > 0 {
+		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, header.BaseFee)
+		w.commitTransactions(env, txs, w.coinbase)
+	}
+	if len(remoteTxs) > 0 {
+		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, header.BaseFee)
+		w.commitTransactions(env, txs, w.coinbase)
+	}
+
+	return w.commit(env)
+}
+
+// prepareHeader returns the header of the block built on [parent] at [tstart], carrying [oraclePrices]
+// and [feedValues]. The caller must hold [w.mu].
+func (w *worker) prepareHeader(parent *types.Block, oraclePrices []byte, feedValues []*types.FeedValue, tstart time.Time) (*types.Header, error) {
+	timestamp := tstart.Unix()
 	// Note: in order to support asynchronous block production, blocks are allowed to have
 	// the same timestamp as their parent. This allows more than one block to be produced
 	// per second.
@@ -122,8 +188,13 @@ func (w *worker) commitNewWork(oraclePrices []byte) (*types.Block, error) {
 		timestamp = int64(parent.Time())
 	}
 
+	feeConfig, err := w.chain.GetFeeConfigAt(parent.Header())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee config: %w", err)
+	}
+
 	var gasLimit uint64
-	configuredGasLimit := w.chainConfig.GetFeeConfig().GasLimit.Uint64()
+	configuredGasLimit := feeConfig.GasLimit.Uint64()
 	if w.chainConfig.IsSubnetEVM(big.NewInt(timestamp)) {
 		gasLimit = configuredGasLimit
 	} else {
@@ -132,6 +203,11 @@ func (w *worker) commitNewWork(oraclePrices []byte) (*types.Block, error) {
 		gasLimit = core.CalcGasLimit(parent.GasUsed(), parent.GasLimit(), configuredGasLimit, configuredGasLimit)
 	}
 
+	encodedValues, err := types.EncodeFeedValues(feedValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode typed oracle values: %w", err)
+	}
+
 	num := parent.Number()
 
 	header := &types.Header{
@@ -141,69 +217,100 @@ func (w *worker) commitNewWork(oraclePrices []byte) (*types.Block, error) {
 		Extra:      nil,
 		Time:       uint64(timestamp),
 		Prices:     oraclePrices,
+		FeedValues: encodedValues,
 	}
 
-	bigTimestamp := big.NewInt(timestamp)
-	if w.chainConfig.IsSubnetEVM(bigTimestamp) {
-		var err error
-		header.Extra, header.BaseFee, err = dummy.CalcBaseFee(w.chainConfig, parent.Header(), uint64(timestamp))
+	if w.chainConfig.IsSubnetEVM(big.NewInt(timestamp)) {
+		header.Extra, header.BaseFee, err = dummy.CalcBaseFee(w.chainConfig, feeConfig, parent.Header(), uint64(timestamp))
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate new base fee: %w", err)
 		}
 	}
 
-	if w.coinbase == (common.Address{}) {
-		return nil, errors.New("cannot mine without etherbase")
-	}
 	header.Coinbase = w.coinbase
 	if err := w.engine.Prepare(w.chain, header); err != nil {
 		return nil, fmt.Errorf("failed to prepare header for mining: %w", err)
 	}
+	return header, nil
+}
 
-	env, err := w.createCurrentEnvironment(parent, header, tstart)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new current environment: %w", err)
-	}
-	// Configure any stateful precompiles that should go into effect during this block.
-	w.chainConfig.CheckConfigurePrecompiles(new(big.Int).SetUint64(parent.Time()), bigTimestamp, env.state)
+// setPriceSource sets the source of the oracle prices carried by the pending block.
+func (w *worker) setPriceSource(source func() ([]byte, error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.priceSource = source
+}
 
-	// Fill the block with all available pending transactions.
-	pending := w.eth.TxPool().Pending(true)
+// setFeedValueSource sets the source of the typed oracle values carried by the generated and pending blocks.
+func (w *worker) setFeedValueSource(source func() ([]*types.FeedValue, error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.feedValueSource = source
+}
 
-	// Split the pending transactions into locals and remotes
-	localTxs := make(map[common.Address]types.Transactions)
-	remoteTxs := pending
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remoteTxs[account]; len(txs) > 0 {
-			delete(remoteTxs, account)
-			localTxs[account] = txs
-		}
+// oracleSources returns the sources of the oracle prices and of the typed oracle values. The sources
+// are called without holding [w.mu], so that a slow source does not block the other users of the lock.
+func (w *worker) oracleSources() (func() ([]byte, error), func() ([]*types.FeedValue, error)) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.priceSource, w.feedValueSource
+}
+
+// fetchFeedValues returns the typed oracle values reported by [source], or none if [source] is nil.
+func fetchFeedValues(source func() ([]*types.FeedValue, error)) ([]*types.FeedValue, error) {
+	if source == nil {
+		return nil, nil
 	}
+	return source()
+}
 
-	////////////// GATTACA MOD - Commit prices to state //////
-	prices, err := streamer.BytesToPrices(header.Prices)
+// pending returns the pending block and its state, or nil if no price source is set. The pending block
+// carries the prices currently reported by the price source, which are written to its state along with
+// the precompile configuration of the block, but none of the pending transactions. The returned state is
+// a copy that the caller may modify.
+func (w *worker) pending() (*types.Block, *state.StateDB, error) {
+	priceSource, feedValueSource := w.oracleSources()
+	if priceSource == nil {
+		return nil, nil, nil
+	}
+	oraclePrices, err := priceSource()
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to get pending prices: %w", err)
 	}
-
-	for _, price := range prices {
-		err = precompile.WritePriceToState(env.state, price)
-		if err != nil {
-			return nil, err
-		}
+	feedValues, err := fetchFeedValues(feedValueSource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pending typed oracle values: %w", err)
+	}
+	parent := w.chain.CurrentBlock()
+	w.mu.RLock()
+	header, err := w.prepareHeader(parent, oraclePrices, feedValues, w.clock.Time())
+	w.mu.RUnlock()
+	if err != nil {
+		return nil, nil, err
 	}
-	/////////////////////////////////////////////////////////
 
-	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, header.BaseFee)
-		w.commitTransactions(env, txs, w.coinbase)
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+
+	// The header timestamp selects the precompile configuration and the update policies applied to
+	// the pending state, so the pending block is rebuilt when it changes.
+	if w.pendingBlock != nil {
+		pending := w.pendingBlock.Header()
+		if pending.ParentHash == header.ParentHash && pending.Time == header.Time && pending.Coinbase == header.Coinbase &&
+			bytes.Equal(pending.Prices, header.Prices) && bytes.Equal(pending.FeedValues, header.FeedValues) {
+			return w.pendingBlock, w.pendingState.Copy(), nil
+		}
 	}
-	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, header.BaseFee)
-		w.commitTransactions(env, txs, w.coinbase)
+	statedb, err := w.chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return w.commit(env)
+	// The pending block only previews the oracle data of the next block, so the callbacks
+	// of the price triggers are not executed.
+	block := types.NewBlockWithHeader(header)
+	core.ApplyBlockOracleData(w.chainConfig, block, parent.Header(), statedb)
+	w.pendingBlock, w.pendingState = block, statedb
+	return block, statedb.Copy(), nil
 }
 
 func (w *worker) createCurrentEnvironment(parent *types.Block, header *types.Header, tstart time.Time) (*environment, error) {
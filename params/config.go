@@ -33,6 +33,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/commontype"
 	"github.com/gattaca-com/oracle-evm/precompile"
 	"github.com/gattaca-com/oracle-evm/utils"
 )
@@ -63,6 +64,11 @@ var (
 		MaxBlockGasCost:  big.NewInt(1_000_000),
 		BlockGasCostStep: big.NewInt(200_000),
 	}
+
+	// DefaultOracleFeeds are the feeds registered with the price oracle by the default chain configs.
+	DefaultOracleFeeds = []precompile.OracleFeedConfig{
+		{Id: common.Hash(precompile.AVAX_USD), Symbol: "AVAX/USD", Decimals: 8},
+	}
 )
 
 var (
@@ -82,10 +88,11 @@ var (
 		SubnetEVMTimestamp:  big.NewInt(0),
 		FeeConfig:           DefaultFeeConfig,
 		AllowFeeRecipients:  false,
+		PriceOracleConfig:   precompile.NewPriceOracleConfig(big.NewInt(0), nil, DefaultOracleFeeds),
 	}
 
-	TestChainConfig        = &ChainConfig{big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), DefaultFeeConfig, false, precompile.PriceOracleConfig{}}
-	TestPreSubnetEVMConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, DefaultFeeConfig, false, precompile.PriceOracleConfig{}}
+	TestChainConfig        = &ChainConfig{big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), DefaultFeeConfig, false, nil, nil, nil, nil, nil, precompile.NewPriceOracleConfig(big.NewInt(0), nil, DefaultOracleFeeds), nil, nil}
+	TestPreSubnetEVMConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, DefaultFeeConfig, false, nil, nil, nil, nil, nil, precompile.NewPriceOracleConfig(big.NewInt(0), nil, DefaultOracleFeeds), nil, nil}
 )
 
 // ChainConfig is the core config which determines the blockchain settings.
@@ -116,29 +123,62 @@ type ChainConfig struct {
 	FeeConfig          *FeeConfig `json:"feeConfig,omitempty"`
 	AllowFeeRecipients bool       `json:"allowFeeRecipients,omitempty"` // Allows fees to be collected by block builders.
 
-	PriceOracleConfig               precompile.PriceOracleConfig               `json:"priceOracleConfig,omitempty"`               // Config for the price oracle precompile
-}
-
-type FeeConfig struct {
-	GasLimit        *big.Int `json:"gasLimit,omitempty"`
-	TargetBlockRate uint64   `json:"targetBlockRate,omitempty"`
+	USDFeeConfig *USDFeeConfig `json:"usdFeeConfig,omitempty"` // Pegs the minimum base fee to USD using the price oracle.
 
-	MinBaseFee               *big.Int `json:"minBaseFee,omitempty"`
-	TargetGas                *big.Int `json:"targetGas,omitempty"`
-	BaseFeeChangeDenominator *big.Int `json:"baseFeeChangeDenominator,omitempty"`
+	ContractDeployerAllowListConfig *precompile.ContractDeployerAllowListConfig `json:"contractDeployerAllowListConfig,omitempty"` // Config for the contract deployer allow list precompile
+	ContractNativeMinterConfig      *precompile.ContractNativeMinterConfig      `json:"contractNativeMinterConfig,omitempty"`      // Config for the native minter precompile
+	TxAllowListConfig               *precompile.TxAllowListConfig               `json:"txAllowListConfig,omitempty"`               // Config for the tx allow list precompile
+	FeeManagerConfig                *precompile.FeeConfigManagerConfig          `json:"feeManagerConfig,omitempty"`                // Config for the fee manager precompile
+	PriceOracleConfig               *precompile.PriceOracleConfig               `json:"priceOracleConfig,omitempty"`               // Config for the price oracle precompile
+	PriceTriggerConfig              *precompile.PriceTriggerConfig              `json:"priceTriggerConfig,omitempty"`              // Config for the price trigger precompile
 
-	MinBlockGasCost  *big.Int `json:"minBlockGasCost,omitempty"`
-	MaxBlockGasCost  *big.Int `json:"maxBlockGasCost,omitempty"`
-	BlockGasCostStep *big.Int `json:"blockGasCostStep,omitempty"`
+	// PrecompileUpgrades enable, disable or reconfigure stateful precompiles at the given timestamps,
+	// after any config specified directly above has taken effect.
+	PrecompileUpgrades []PrecompileUpgrade `json:"precompileUpgrades,omitempty"`
 }
 
+// FeeConfig is defined in [commontype] so that it can be shared with the fee manager precompile.
+type FeeConfig = commontype.FeeConfig
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	feeBytes, err := json.Marshal(c.FeeConfig)
 	if err != nil {
 		feeBytes = []byte("cannot unmarshal FeeConfig")
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Subnet EVM: %v, FeeConfig: %v, AllowFeeRecipients: %v, PriceOracleConfig: %v, Engine: Dummy Consensus Engine}",
+	usdFeeBytes, err := json.Marshal(c.USDFeeConfig)
+	if err != nil {
+		usdFeeBytes = []byte("cannot unmarshal USDFeeConfig")
+	}
+	deployerBytes, err := json.Marshal(c.ContractDeployerAllowListConfig)
+	if err != nil {
+		deployerBytes = []byte("cannot unmarshal ContractDeployerAllowListConfig")
+	}
+	minterBytes, err := json.Marshal(c.ContractNativeMinterConfig)
+	if err != nil {
+		minterBytes = []byte("cannot unmarshal ContractNativeMinterConfig")
+	}
+	txAllowListBytes, err := json.Marshal(c.TxAllowListConfig)
+	if err != nil {
+		txAllowListBytes = []byte("cannot unmarshal TxAllowListConfig")
+	}
+	feeManagerBytes, err := json.Marshal(c.FeeManagerConfig)
+	if err != nil {
+		feeManagerBytes = []byte("cannot unmarshal FeeManagerConfig")
+	}
+	oracleBytes, err := json.Marshal(c.PriceOracleConfig)
+	if err != nil {
+		oracleBytes = []byte("cannot unmarshal PriceOracleConfig")
+	}
+	triggerBytes, err := json.Marshal(c.PriceTriggerConfig)
+	if err != nil {
+		triggerBytes = []byte("cannot unmarshal PriceTriggerConfig")
+	}
+	upgradeBytes, err := json.Marshal(c.PrecompileUpgrades)
+	if err != nil {
+		upgradeBytes = []byte("cannot unmarshal PrecompileUpgrades")
+	}
+	return fmt.Sprintf("{ChainID: %v Homestead: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Subnet EVM: %v, FeeConfig: %v, AllowFeeRecipients: %v, USDFeeConfig: %v, ContractDeployerAllowListConfig: %v, ContractNativeMinterConfig: %v, TxAllowListConfig: %v, FeeManagerConfig: %v, PriceOracleConfig: %v, PriceTriggerConfig: %v, PrecompileUpgrades: %v, Engine: Dummy Consensus Engine}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.EIP150Block,
@@ -152,7 +192,14 @@ func (c *ChainConfig) String() string {
 		c.SubnetEVMTimestamp,
 		string(feeBytes),
 		c.AllowFeeRecipients,
-		c.PriceOracleConfig,
+		string(usdFeeBytes),
+		string(deployerBytes),
+		string(minterBytes),
+		string(txAllowListBytes),
+		string(feeManagerBytes),
+		string(oracleBytes),
+		string(triggerBytes),
+		string(upgradeBytes),
 	)
 }
 
@@ -208,10 +255,39 @@ func (c *ChainConfig) IsSubnetEVM(blockTimestamp *big.Int) bool {
 	return utils.IsForked(c.SubnetEVMTimestamp, blockTimestamp)
 }
 
-// IsContractNativeMinter returns whether [blockTimestamp] is either equal to the NativeMinter fork block timestamp or greater.
+// IsContractDeployerAllowList returns whether the contract deployer allow list is enabled at [blockTimestamp].
+func (c *ChainConfig) IsContractDeployerAllowList(blockTimestamp *big.Int) bool {
+	return c.GetActiveContractDeployerAllowListConfig(blockTimestamp) != nil
+}
+
+// IsContractNativeMinter returns whether the native minter is enabled at [blockTimestamp].
+func (c *ChainConfig) IsContractNativeMinter(blockTimestamp *big.Int) bool {
+	return c.GetActiveContractNativeMinterConfig(blockTimestamp) != nil
+}
+
+// IsTxAllowList returns whether the tx allow list is enabled at [blockTimestamp].
+func (c *ChainConfig) IsTxAllowList(blockTimestamp *big.Int) bool {
+	return c.GetActiveTxAllowListConfig(blockTimestamp) != nil
+}
+
+// IsFeeConfigManager returns whether the fee manager is enabled at [blockTimestamp].
+func (c *ChainConfig) IsFeeConfigManager(blockTimestamp *big.Int) bool {
+	return c.GetActiveFeeConfigManagerConfig(blockTimestamp) != nil
+}
+
+// IsPriceOracle returns whether the price oracle precompile is enabled at [blockTimestamp].
 func (c *ChainConfig) IsPriceOracle(blockTimestamp *big.Int) bool {
-	return true
-	// return utils.IsForked(c.ContractNativeMinterConfig.Timestamp(), blockTimestamp)
+	return c.GetActivePriceOracleConfig(blockTimestamp) != nil
+}
+
+// IsUSDFeePegged returns whether the minimum base fee is pegged to USD at [blockTimestamp].
+func (c *ChainConfig) IsUSDFeePegged(blockTimestamp *big.Int) bool {
+	return utils.IsForked(c.USDFeeConfig.timestamp(), blockTimestamp)
+}
+
+// IsPriceTrigger returns whether the price trigger precompile is enabled at [blockTimestamp].
+func (c *ChainConfig) IsPriceTrigger(blockTimestamp *big.Int) bool {
+	return c.GetActivePriceTriggerConfig(blockTimestamp) != nil
 }
 
 // GetFeeConfig returns the *FeeConfig if it exists, otherwise it returns [DefaultFeeConfig].
@@ -316,6 +392,16 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 	return nil
 }
 
+// Verify returns an error if any of the stateful precompile configs or precompile upgrades in [c] are invalid.
+func (c *ChainConfig) Verify() error {
+	if c.USDFeeConfig != nil {
+		if err := c.USDFeeConfig.Verify(); err != nil {
+			return err
+		}
+	}
+	return c.verifyPrecompileUpgrades()
+}
+
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headHeight *big.Int, headTimestamp *big.Int) *ConfigCompatError {
 	if isForkIncompatible(c.HomesteadBlock, newcfg.HomesteadBlock, headHeight) {
 		return newCompatError("Homestead fork block", c.HomesteadBlock, newcfg.HomesteadBlock)
@@ -356,6 +442,17 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headHeight *big.Int,
 	if isForkIncompatible(c.SubnetEVMTimestamp, newcfg.SubnetEVMTimestamp, headTimestamp) {
 		return newCompatError("SubnetEVM fork block timestamp", c.SubnetEVMTimestamp, newcfg.SubnetEVMTimestamp)
 	}
+	if isForkIncompatible(c.USDFeeConfig.timestamp(), newcfg.USDFeeConfig.timestamp(), headTimestamp) {
+		return newCompatError("USD fee config timestamp", c.USDFeeConfig.timestamp(), newcfg.USDFeeConfig.timestamp())
+	}
+	if c.IsUSDFeePegged(headTimestamp) && !c.USDFeeConfig.Equal(newcfg.USDFeeConfig) {
+		return newCompatError("USD fee config", c.USDFeeConfig.timestamp(), newcfg.USDFeeConfig.timestamp())
+	}
+
+	// Check that the precompile configs that have already taken effect are unchanged.
+	if err := c.checkPrecompilesCompatible(newcfg, headTimestamp); err != nil {
+		return err
+	}
 
 	// TODO verify that the fee config is fully compatible between [c] and [newcfg].
 
@@ -425,7 +522,10 @@ type Rules struct {
 	// Optional stateful precompile rules
 	IsContractDeployerAllowListEnabled bool
 	IsContractNativeMinterEnabled      bool
+	IsTxAllowListEnabled               bool
+	IsFeeConfigManagerEnabled          bool
 	IsPriceOracleEnabled               bool
+	IsPriceTriggerEnabled              bool
 
 	// Precompiles maps addresses to stateful precompiled contracts that are enabled
 	// for this rule set.
@@ -459,34 +559,36 @@ func (c *ChainConfig) AvalancheRules(blockNum, blockTimestamp *big.Int) Rules {
 	rules := c.rules(blockNum)
 
 	rules.IsSubnetEVM = c.IsSubnetEVM(blockTimestamp)
+	rules.IsContractDeployerAllowListEnabled = c.IsContractDeployerAllowList(blockTimestamp)
+	rules.IsContractNativeMinterEnabled = c.IsContractNativeMinter(blockTimestamp)
+	rules.IsTxAllowListEnabled = c.IsTxAllowList(blockTimestamp)
+	rules.IsFeeConfigManagerEnabled = c.IsFeeConfigManager(blockTimestamp)
 	rules.IsPriceOracleEnabled = c.IsPriceOracle(blockTimestamp)
+	rules.IsPriceTriggerEnabled = c.IsPriceTrigger(blockTimestamp)
 
 	// Initialize the stateful precompiles that should be enabled at [blockTimestamp].
 	rules.Precompiles = make(map[common.Address]precompile.StatefulPrecompiledContract)
-	for _, config := range c.enabledStatefulPrecompiles() {
-		if utils.IsForked(config.Timestamp(), blockTimestamp) {
-			rules.Precompiles[config.Address()] = config.Contract()
+	for _, address := range precompile.UsedAddresses {
+		if config := c.getActivePrecompileConfig(address, blockTimestamp); config != nil {
+			rules.Precompiles[address] = config.Contract()
 		}
 	}
 
 	return rules
 }
 
-// enabledStatefulPrecompiles returns a list of stateful precompile configs in the order that they are enabled
-// by block timestamp.
-func (c *ChainConfig) enabledStatefulPrecompiles() []precompile.StatefulPrecompileConfig {
-	statefulPrecompileConfigs := make([]precompile.StatefulPrecompileConfig, 0)
-
-	statefulPrecompileConfigs = append(statefulPrecompileConfigs, &c.PriceOracleConfig)
-
-	return statefulPrecompileConfigs
-}
-
-// CheckConfigurePrecompiles iterates over any stateful precompile configs that go into effect at some point and configures them
-// if they are activated between [parentTimestamp] and [currentTimestamp].
-func (c *ChainConfig) CheckConfigurePrecompiles(parentTimestamp *big.Int, currentTimestamp *big.Int, statedb precompile.StateDB) {
-	// Iterate the enabled stateful precompiles and configure them if needed
-	for _, config := range c.enabledStatefulPrecompiles() {
-		precompile.CheckConfigure(parentTimestamp, currentTimestamp, config, statedb)
+// CheckConfigurePrecompiles applies every stateful precompile config that takes effect during the transition
+// from a block at [parentTimestamp] to the block [blockNumber] at [currentTimestamp]. Configs that enable or
+// reconfigure a precompile are configured and configs that disable a precompile remove its account from [statedb].
+// Note: [parentTimestamp] is nil when configuring the genesis state.
+func (c *ChainConfig) CheckConfigurePrecompiles(parentTimestamp *big.Int, currentTimestamp *big.Int, blockNumber *big.Int, statedb precompile.ConfigurableStateDB) {
+	for _, address := range precompile.UsedAddresses {
+		for _, config := range c.getActivatingPrecompileConfigs(address, parentTimestamp, currentTimestamp) {
+			if config.IsDisabled() {
+				precompile.Disable(address, statedb)
+			} else {
+				precompile.Configure(config, statedb, blockNumber)
+			}
+		}
 	}
 }
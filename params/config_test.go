@@ -30,6 +30,10 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -38,6 +42,28 @@ func TestCheckCompatible(t *testing.T) {
 		headHeight, headTimestamp uint64
 		wantErr                   *ConfigCompatError
 	}
+	oracleReconfiguredAt := func(timestamp int64) *ChainConfig {
+		reconfig := precompile.NewPriceOracleConfig(big.NewInt(timestamp), nil, nil)
+		reconfig.GasSchedule = &precompile.OracleGasSchedule{
+			GetPrice:    precompile.FunctionGasCost{Cold: 2_000, Warm: 100},
+			GetDecimals: precompile.FunctionGasCost{Cold: 2_000, Warm: 100},
+		}
+		return &ChainConfig{
+			PriceOracleConfig:  precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			PrecompileUpgrades: []PrecompileUpgrade{{PriceOracleConfig: reconfig}},
+		}
+	}
+	oracleDisabledAt := func(timestamp int64) *ChainConfig {
+		return &ChainConfig{
+			PriceOracleConfig:  precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			PrecompileUpgrades: []PrecompileUpgrade{{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(timestamp))}},
+		}
+	}
+	usdPeggedAt := func(timestamp int64, minBaseFee int64) *ChainConfig {
+		return &ChainConfig{
+			USDFeeConfig: &USDFeeConfig{BlockTimestamp: big.NewInt(timestamp), MinBaseFee: big.NewInt(minBaseFee)},
+		}
+	}
 	tests := []test{
 		{stored: TestChainConfig, new: TestChainConfig, headHeight: 0, headTimestamp: 0, wantErr: nil},
 		{stored: TestChainConfig, new: TestChainConfig, headHeight: 100, headTimestamp: 1000, wantErr: nil},
@@ -127,6 +153,75 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     0,
 			},
 		},
+		{
+			stored:        oracleReconfiguredAt(100),
+			new:           oracleReconfiguredAt(200),
+			headHeight:    5,
+			headTimestamp: 50,
+			wantErr:       nil,
+		},
+		{
+			stored:        oracleReconfiguredAt(100),
+			new:           oracleReconfiguredAt(100),
+			headHeight:    15,
+			headTimestamp: 150,
+			wantErr:       nil,
+		},
+		{
+			stored:        oracleReconfiguredAt(100),
+			new:           oracleReconfiguredAt(200),
+			headHeight:    15,
+			headTimestamp: 150,
+			wantErr: &ConfigCompatError{
+				What:         "precompile 0x0300000000000000000000000000000000000001 upgrade timestamp",
+				StoredConfig: big.NewInt(100),
+				NewConfig:    nil,
+				RewindTo:     99,
+			},
+		},
+		{
+			stored:        oracleReconfiguredAt(100),
+			new:           oracleDisabledAt(100),
+			headHeight:    15,
+			headTimestamp: 150,
+			wantErr: &ConfigCompatError{
+				What:         "precompile 0x0300000000000000000000000000000000000001 upgrade timestamp",
+				StoredConfig: big.NewInt(100),
+				NewConfig:    big.NewInt(100),
+				RewindTo:     99,
+			},
+		},
+		{
+			stored:        usdPeggedAt(100, 1),
+			new:           usdPeggedAt(200, 2),
+			headHeight:    5,
+			headTimestamp: 50,
+			wantErr:       nil,
+		},
+		{
+			stored:        usdPeggedAt(100, 1),
+			new:           usdPeggedAt(200, 1),
+			headHeight:    15,
+			headTimestamp: 150,
+			wantErr: &ConfigCompatError{
+				What:         "USD fee config timestamp",
+				StoredConfig: big.NewInt(100),
+				NewConfig:    big.NewInt(200),
+				RewindTo:     99,
+			},
+		},
+		{
+			stored:        usdPeggedAt(100, 1),
+			new:           usdPeggedAt(100, 2),
+			headHeight:    15,
+			headTimestamp: 150,
+			wantErr: &ConfigCompatError{
+				What:         "USD fee config",
+				StoredConfig: big.NewInt(100),
+				NewConfig:    big.NewInt(100),
+				RewindTo:     99,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -136,3 +231,295 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyPrecompileUpgrades(t *testing.T) {
+	admins := []common.Address{{1}}
+	for name, test := range map[string]struct {
+		genesisConfig *precompile.PriceOracleConfig
+		upgrades      []PrecompileUpgrade
+		expectedErr   string
+	}{
+		"enable, reconfigure, disable and re-enable": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(10), admins, nil)},
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(20))},
+				{PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(30), nil, nil)},
+			},
+		},
+		"enable by upgrade only": {
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(10), nil, nil)},
+			},
+		},
+		"disable without enabling": {
+			upgrades: []PrecompileUpgrade{
+				{FeeManagerConfig: precompile.NewDisableFeeConfigManagerConfig(big.NewInt(10))},
+			},
+			expectedErr: "disables a precompile that is not enabled",
+		},
+		"disable legacy price oracle": {
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+			},
+		},
+		"disable price oracle that is not enabled": {
+			genesisConfig: &precompile.PriceOracleConfig{},
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+			},
+			expectedErr: "disables a precompile that is not enabled",
+		},
+		"disable twice": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(20))},
+			},
+			expectedErr: "disables a precompile that is not enabled",
+		},
+		"disable a genesis config without a timestamp": {
+			genesisConfig: &precompile.PriceOracleConfig{},
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+			},
+			expectedErr: "disables a precompile that is not enabled",
+		},
+		"upgrade before genesis config": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(10), nil, nil),
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(5))},
+			},
+			expectedErr: "must be after timestamp 10",
+		},
+		"upgrades at the same timestamp": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(10), nil, nil)},
+				{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))},
+			},
+			expectedErr: "must be after timestamp 10",
+		},
+		"upgrade without a timestamp": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			upgrades: []PrecompileUpgrade{
+				{PriceOracleConfig: precompile.NewPriceOracleConfig(nil, nil, nil)},
+			},
+			expectedErr: "missing a block timestamp",
+		},
+		"duplicate admin": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), []common.Address{{1}, {1}}, nil),
+			expectedErr:   "duplicate allow list admin",
+		},
+		"zero address admin": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), []common.Address{{}}, nil),
+			expectedErr:   "cannot be the zero address",
+		},
+		"duplicate feed id": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "AVAX/USD", Decimals: 8},
+				{Symbol: "ETH/USD", Decimals: 8},
+			}),
+			expectedErr: "duplicate price oracle feed id",
+		},
+		"duplicate feed symbol": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "AVAX/USD", Decimals: 8},
+				{Id: common.Hash{1}, Symbol: "AVAX/USD", Decimals: 8},
+			}),
+			expectedErr: "duplicate price oracle feed symbol",
+		},
+		"duplicate publisher": {
+			genesisConfig: &precompile.PriceOracleConfig{
+				UpgradeableConfig: precompile.UpgradeableConfig{BlockTimestamp: big.NewInt(0)},
+				Publishers: []precompile.OraclePublisherConfig{
+					{Address: common.Address{1}, MinPublishInterval: 10},
+					{Address: common.Address{1}, MinPublishInterval: 20},
+				},
+			},
+			expectedErr: "duplicate price oracle publisher",
+		},
+		"feed symbol too long": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "AVAX/USD/EURO", Decimals: 8},
+			}),
+			expectedErr: "exceeds the maximum length",
+		},
+		"invalid feed source": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSource(2)},
+			}),
+			expectedErr: "invalid source",
+		},
+		"pushed feed with an update policy": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "EUR/USD", Decimals: 6, Source: precompile.FeedSourcePushed, Heartbeat: 60},
+			}),
+			expectedErr: "cannot have an update policy",
+		},
+		"pushed typed feed": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "SOFR", Decimals: 4, Source: precompile.FeedSourcePushed, Type: precompile.FeedTypeInt256},
+			}),
+			expectedErr: "must be streamed",
+		},
+		"typed feed with an initial price": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, []precompile.OracleFeedConfig{
+				{Symbol: "POR/OK", Type: precompile.FeedTypeBool, InitialPrice: &precompile.OracleInitialPrice{Price: 1}},
+			}),
+			expectedErr: "cannot have an update policy or an initial price",
+		},
+		"enable and disable fee manager": {
+			upgrades: []PrecompileUpgrade{
+				{FeeManagerConfig: precompile.NewFeeConfigManagerConfig(big.NewInt(10), admins, DefaultFeeConfig)},
+				{FeeManagerConfig: precompile.NewDisableFeeConfigManagerConfig(big.NewInt(20))},
+			},
+		},
+		"fee manager without an initial fee config": {
+			upgrades: []PrecompileUpgrade{
+				{FeeManagerConfig: precompile.NewFeeConfigManagerConfig(big.NewInt(10), admins, nil)},
+			},
+			expectedErr: "initial fee config must be specified",
+		},
+		"fee manager with an invalid initial fee config": {
+			upgrades: []PrecompileUpgrade{
+				{FeeManagerConfig: precompile.NewFeeConfigManagerConfig(big.NewInt(10), admins, &FeeConfig{
+					GasLimit:                 big.NewInt(8_000_000),
+					TargetBlockRate:          2,
+					MinBaseFee:               big.NewInt(25_000_000_000),
+					TargetGas:                big.NewInt(15_000_000),
+					BaseFeeChangeDenominator: big.NewInt(36),
+					MinBlockGasCost:          big.NewInt(2_000_000),
+					MaxBlockGasCost:          big.NewInt(1_000_000),
+					BlockGasCostStep:         big.NewInt(200_000),
+				})},
+			},
+			expectedErr: "cannot be greater than maxBlockGasCost",
+		},
+		"enable and disable price triggers": {
+			upgrades: []PrecompileUpgrade{
+				{PriceTriggerConfig: precompile.NewPriceTriggerConfig(big.NewInt(10), 0)},
+				{PriceTriggerConfig: precompile.NewDisablePriceTriggerConfig(big.NewInt(20))},
+			},
+		},
+		"price triggers with a gas budget below the trigger gas limit": {
+			upgrades: []PrecompileUpgrade{
+				{PriceTriggerConfig: precompile.NewPriceTriggerConfig(big.NewInt(10), precompile.MaxPriceTriggerGasLimit-1)},
+			},
+			expectedErr: "cannot be less than the max trigger gas limit",
+		},
+		"upgrade without a config": {
+			genesisConfig: precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil),
+			upgrades:      []PrecompileUpgrade{{}},
+			expectedErr:   "must specify exactly one precompile config",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := &ChainConfig{
+				PriceOracleConfig:  test.genesisConfig,
+				PrecompileUpgrades: test.upgrades,
+			}
+			err := config.Verify()
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyUSDFeeConfig(t *testing.T) {
+	for name, test := range map[string]struct {
+		config      *USDFeeConfig
+		expectedErr string
+	}{
+		"valid": {
+			config: &USDFeeConfig{
+				BlockTimestamp:       big.NewInt(0),
+				MinBaseFee:           big.NewInt(500_000_000_000),
+				MinBaseFeeFloor:      big.NewInt(1_000_000_000),
+				MinBaseFeeCeiling:    big.NewInt(1_000_000_000_000),
+				SmoothingDenominator: big.NewInt(8),
+			},
+		},
+		"missing timestamp": {
+			config:      &USDFeeConfig{MinBaseFee: big.NewInt(1)},
+			expectedErr: "must specify a block timestamp",
+		},
+		"missing min base fee": {
+			config:      &USDFeeConfig{BlockTimestamp: big.NewInt(0)},
+			expectedErr: "minBaseFee must be positive",
+		},
+		"floor above ceiling": {
+			config: &USDFeeConfig{
+				BlockTimestamp:    big.NewInt(0),
+				MinBaseFee:        big.NewInt(1),
+				MinBaseFeeFloor:   big.NewInt(2),
+				MinBaseFeeCeiling: big.NewInt(1),
+			},
+			expectedErr: "cannot be greater than minBaseFeeCeiling",
+		},
+		"zero smoothing denominator": {
+			config:      &USDFeeConfig{BlockTimestamp: big.NewInt(0), MinBaseFee: big.NewInt(1), SmoothingDenominator: big.NewInt(0)},
+			expectedErr: "smoothingDenominator must be positive",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := (&ChainConfig{USDFeeConfig: test.config}).Verify()
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestActivePrecompileConfig(t *testing.T) {
+	reconfig := precompile.NewPriceOracleConfig(big.NewInt(10), []common.Address{{1}}, nil)
+	reconfig.GasSchedule = &precompile.OracleGasSchedule{
+		GetPrice:    precompile.FunctionGasCost{Cold: 2_000, Warm: 100},
+		GetDecimals: precompile.FunctionGasCost{Cold: 2_000, Warm: 100},
+	}
+	config := &ChainConfig{
+		PriceOracleConfig: precompile.NewPriceOracleConfig(big.NewInt(5), nil, nil),
+		PrecompileUpgrades: []PrecompileUpgrade{
+			{PriceOracleConfig: reconfig},
+			{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(20))},
+		},
+	}
+
+	assert.False(t, config.IsPriceOracle(big.NewInt(0)))
+	assert.Same(t, config.PriceOracleConfig, config.GetActivePriceOracleConfig(big.NewInt(5)))
+	assert.Same(t, reconfig, config.GetActivePriceOracleConfig(big.NewInt(19)))
+	assert.False(t, config.IsPriceOracle(big.NewInt(20)))
+
+	assert.NotContains(t, config.AvalancheRules(common.Big0, big.NewInt(25)).Precompiles, precompile.PriceOracleAddress)
+	assert.Contains(t, config.AvalancheRules(common.Big0, big.NewInt(15)).Precompiles, precompile.PriceOracleAddress)
+	// The gas schedule of the price oracle changes with the config in effect.
+	assert.Same(t, precompile.PriceOraclePreCompile, config.AvalancheRules(common.Big0, big.NewInt(5)).Precompiles[precompile.PriceOracleAddress])
+	assert.Same(t, reconfig.Contract(), config.AvalancheRules(common.Big0, big.NewInt(15)).Precompiles[precompile.PriceOracleAddress])
+	assert.NotSame(t, precompile.PriceOraclePreCompile, reconfig.Contract())
+	assert.Len(t, config.getActivatingPrecompileConfigs(precompile.PriceOracleAddress, big.NewInt(5), big.NewInt(20)), 2)
+}
+
+func TestLegacyPriceOracleConfig(t *testing.T) {
+	// Chains without a price oracle config run the price oracle since genesis.
+	legacy := &ChainConfig{}
+	assert.True(t, legacy.IsPriceOracle(big.NewInt(0)))
+	assert.Contains(t, legacy.AvalancheRules(common.Big0, big.NewInt(0)).Precompiles, precompile.PriceOracleAddress)
+	assert.Len(t, legacy.getActivatingPrecompileConfigs(precompile.PriceOracleAddress, nil, big.NewInt(0)), 1)
+	assert.Empty(t, legacy.GetOracleFeeds(big.NewInt(0)))
+
+	// Upgrades apply on top of the legacy config.
+	legacy.PrecompileUpgrades = []PrecompileUpgrade{{PriceOracleConfig: precompile.NewDisablePriceOracleConfig(big.NewInt(10))}}
+	assert.NoError(t, legacy.Verify())
+	assert.True(t, legacy.IsPriceOracle(big.NewInt(9)))
+	assert.False(t, legacy.IsPriceOracle(big.NewInt(10)))
+
+	// A price oracle config without a block timestamp never enables it.
+	disabled := &ChainConfig{PriceOracleConfig: &precompile.PriceOracleConfig{}}
+	assert.False(t, disabled.IsPriceOracle(big.NewInt(0)))
+	assert.NotContains(t, disabled.AvalancheRules(common.Big0, big.NewInt(0)).Precompiles, precompile.PriceOracleAddress)
+}
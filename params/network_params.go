@@ -42,6 +42,14 @@ const (
 	// considered probably final and its rotated bits are calculated.
 	BloomConfirms = 256
 
+	// PriceHistoryBlocks is the number of blocks in a section of the price history
+	// index backfilled at once.
+	PriceHistoryBlocks uint64 = 4096
+
+	// PriceHistoryConfirms is the number of confirmation blocks before a section of
+	// the price history index is backfilled.
+	PriceHistoryConfirms = 256
+
 	// CHTFrequency is the block frequency for creating CHTs
 	CHTFrequency = 32768
 
@@ -0,0 +1,292 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/precompile"
+	"github.com/gattaca-com/oracle-evm/utils"
+)
+
+var errNoPrecompileConfig = errors.New("precompile upgrade must specify exactly one precompile config")
+
+// legacyPriceOracleConfig is the price oracle config of chains that do not specify one, which ran the
+// price oracle since genesis before it could be configured. To run a chain without the price oracle,
+// specify a price oracle config without a block timestamp.
+var legacyPriceOracleConfig = precompile.NewPriceOracleConfig(big.NewInt(0), nil, nil)
+
+// PrecompileUpgrade is a helper struct embedded in [ChainConfig.PrecompileUpgrades], representing
+// each of the possible stateful precompile types that can be enabled, disabled or reconfigured
+// as a network upgrade.
+type PrecompileUpgrade struct {
+	ContractDeployerAllowListConfig *precompile.ContractDeployerAllowListConfig `json:"contractDeployerAllowListConfig,omitempty"` // Config for the contract deployer allow list precompile
+	ContractNativeMinterConfig      *precompile.ContractNativeMinterConfig      `json:"contractNativeMinterConfig,omitempty"`      // Config for the native minter precompile
+	TxAllowListConfig               *precompile.TxAllowListConfig               `json:"txAllowListConfig,omitempty"`               // Config for the tx allow list precompile
+	FeeManagerConfig                *precompile.FeeConfigManagerConfig          `json:"feeManagerConfig,omitempty"`                // Config for the fee manager precompile
+	PriceOracleConfig               *precompile.PriceOracleConfig               `json:"priceOracleConfig,omitempty"`               // Config for the price oracle precompile
+	PriceTriggerConfig              *precompile.PriceTriggerConfig              `json:"priceTriggerConfig,omitempty"`              // Config for the price trigger precompile
+}
+
+// getByAddress returns the precompile config set in [p] for [address], or nil if [p] does not
+// configure the precompile at [address].
+func (p *PrecompileUpgrade) getByAddress(address common.Address) precompile.StatefulPrecompileConfig {
+	switch address {
+	case precompile.ContractDeployerAllowListAddress:
+		if p.ContractDeployerAllowListConfig != nil {
+			return p.ContractDeployerAllowListConfig
+		}
+	case precompile.ContractNativeMinterAddress:
+		if p.ContractNativeMinterConfig != nil {
+			return p.ContractNativeMinterConfig
+		}
+	case precompile.TxAllowListAddress:
+		if p.TxAllowListConfig != nil {
+			return p.TxAllowListConfig
+		}
+	case precompile.FeeConfigManagerAddress:
+		if p.FeeManagerConfig != nil {
+			return p.FeeManagerConfig
+		}
+	case precompile.PriceOracleAddress:
+		if p.PriceOracleConfig != nil {
+			return p.PriceOracleConfig
+		}
+	case precompile.PriceTriggerAddress:
+		if p.PriceTriggerConfig != nil {
+			return p.PriceTriggerConfig
+		}
+	}
+	return nil
+}
+
+// configs returns all of the precompile configs set in [p].
+func (p *PrecompileUpgrade) configs() []precompile.StatefulPrecompileConfig {
+	configs := make([]precompile.StatefulPrecompileConfig, 0, len(precompile.UsedAddresses))
+	for _, address := range precompile.UsedAddresses {
+		if config := p.getByAddress(address); config != nil {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+// genesisPrecompileConfig returns the config for the precompile at [address] specified directly in
+// the chain config, or nil if there is none.
+func (c *ChainConfig) genesisPrecompileConfig(address common.Address) precompile.StatefulPrecompileConfig {
+	switch address {
+	case precompile.ContractDeployerAllowListAddress:
+		if c.ContractDeployerAllowListConfig != nil {
+			return c.ContractDeployerAllowListConfig
+		}
+	case precompile.ContractNativeMinterAddress:
+		if c.ContractNativeMinterConfig != nil {
+			return c.ContractNativeMinterConfig
+		}
+	case precompile.TxAllowListAddress:
+		if c.TxAllowListConfig != nil {
+			return c.TxAllowListConfig
+		}
+	case precompile.FeeConfigManagerAddress:
+		if c.FeeManagerConfig != nil {
+			return c.FeeManagerConfig
+		}
+	case precompile.PriceOracleAddress:
+		if c.PriceOracleConfig != nil {
+			return c.PriceOracleConfig
+		}
+		return legacyPriceOracleConfig
+	case precompile.PriceTriggerAddress:
+		if c.PriceTriggerConfig != nil {
+			return c.PriceTriggerConfig
+		}
+	}
+	return nil
+}
+
+// getActivatingPrecompileConfigs returns the configs for the precompile at [address] that take effect
+// during the transition from a block with timestamp [from] to a block with timestamp [to], in the order
+// in which they are applied. The config specified directly in the chain config is always applied first,
+// followed by [c.PrecompileUpgrades] in order.
+// Note: [from] may be nil to return all of the configs that took effect up to and including [to].
+func (c *ChainConfig) getActivatingPrecompileConfigs(address common.Address, from *big.Int, to *big.Int) []precompile.StatefulPrecompileConfig {
+	configs := make([]precompile.StatefulPrecompileConfig, 0)
+	if config := c.genesisPrecompileConfig(address); config != nil && utils.IsForkTransition(config.Timestamp(), from, to) {
+		configs = append(configs, config)
+	}
+	for _, upgrade := range c.PrecompileUpgrades {
+		if config := upgrade.getByAddress(address); config != nil && utils.IsForkTransition(config.Timestamp(), from, to) {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+// getActivePrecompileConfig returns the config for the precompile at [address] that is in effect at
+// [blockTimestamp], or nil if the precompile is not enabled at [blockTimestamp].
+func (c *ChainConfig) getActivePrecompileConfig(address common.Address, blockTimestamp *big.Int) precompile.StatefulPrecompileConfig {
+	configs := c.getActivatingPrecompileConfigs(address, nil, blockTimestamp)
+	if len(configs) == 0 {
+		return nil
+	}
+	if config := configs[len(configs)-1]; !config.IsDisabled() {
+		return config
+	}
+	return nil
+}
+
+// GetActiveContractDeployerAllowListConfig returns the contract deployer allow list config in effect at
+// [blockTimestamp], or nil if the allow list is not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActiveContractDeployerAllowListConfig(blockTimestamp *big.Int) *precompile.ContractDeployerAllowListConfig {
+	if config := c.getActivePrecompileConfig(precompile.ContractDeployerAllowListAddress, blockTimestamp); config != nil {
+		return config.(*precompile.ContractDeployerAllowListConfig)
+	}
+	return nil
+}
+
+// GetActiveContractNativeMinterConfig returns the native minter config in effect at [blockTimestamp], or nil
+// if the native minter is not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActiveContractNativeMinterConfig(blockTimestamp *big.Int) *precompile.ContractNativeMinterConfig {
+	if config := c.getActivePrecompileConfig(precompile.ContractNativeMinterAddress, blockTimestamp); config != nil {
+		return config.(*precompile.ContractNativeMinterConfig)
+	}
+	return nil
+}
+
+// GetActiveTxAllowListConfig returns the tx allow list config in effect at [blockTimestamp], or nil if
+// the allow list is not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActiveTxAllowListConfig(blockTimestamp *big.Int) *precompile.TxAllowListConfig {
+	if config := c.getActivePrecompileConfig(precompile.TxAllowListAddress, blockTimestamp); config != nil {
+		return config.(*precompile.TxAllowListConfig)
+	}
+	return nil
+}
+
+// GetActiveFeeConfigManagerConfig returns the fee manager config in effect at [blockTimestamp], or nil if
+// the fee manager is not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActiveFeeConfigManagerConfig(blockTimestamp *big.Int) *precompile.FeeConfigManagerConfig {
+	if config := c.getActivePrecompileConfig(precompile.FeeConfigManagerAddress, blockTimestamp); config != nil {
+		return config.(*precompile.FeeConfigManagerConfig)
+	}
+	return nil
+}
+
+// GetActivePriceOracleConfig returns the price oracle config in effect at [blockTimestamp], or nil if
+// the price oracle is not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActivePriceOracleConfig(blockTimestamp *big.Int) *precompile.PriceOracleConfig {
+	if config := c.getActivePrecompileConfig(precompile.PriceOracleAddress, blockTimestamp); config != nil {
+		return config.(*precompile.PriceOracleConfig)
+	}
+	return nil
+}
+
+// GetOracleFeeds returns the feeds registered with the price oracle by the configs that took effect up to and
+// including [blockTimestamp], in registration order. As in the feed registry, a feed whose id or symbol has
+// already been registered is ignored.
+func (c *ChainConfig) GetOracleFeeds(blockTimestamp *big.Int) []precompile.OracleFeedConfig {
+	var (
+		feeds   []precompile.OracleFeedConfig
+		ids     = make(map[common.Hash]struct{})
+		symbols = make(map[string]struct{})
+	)
+	for _, config := range c.getActivatingPrecompileConfigs(precompile.PriceOracleAddress, nil, blockTimestamp) {
+		for _, feed := range config.(*precompile.PriceOracleConfig).InitialFeeds {
+			if _, exists := ids[feed.Id]; exists {
+				continue
+			}
+			if _, exists := symbols[feed.Symbol]; exists {
+				continue
+			}
+			ids[feed.Id] = struct{}{}
+			symbols[feed.Symbol] = struct{}{}
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds
+}
+
+// GetActivePriceTriggerConfig returns the price trigger config in effect at [blockTimestamp], or nil if
+// price triggers are not enabled at [blockTimestamp].
+func (c *ChainConfig) GetActivePriceTriggerConfig(blockTimestamp *big.Int) *precompile.PriceTriggerConfig {
+	if config := c.getActivePrecompileConfig(precompile.PriceTriggerAddress, blockTimestamp); config != nil {
+		return config.(*precompile.PriceTriggerConfig)
+	}
+	return nil
+}
+
+// verifyPrecompileUpgrades checks that the precompile configs in [c] are valid and that, for each
+// precompile, they take effect in order of strictly increasing timestamps, only disable an enabled
+// precompile and do not disable a precompile that is already disabled.
+func (c *ChainConfig) verifyPrecompileUpgrades() error {
+	for i, upgrade := range c.PrecompileUpgrades {
+		configs := upgrade.configs()
+		if len(configs) != 1 {
+			return fmt.Errorf("precompile upgrade %d: %w", i, errNoPrecompileConfig)
+		}
+		if configs[0].Timestamp() == nil {
+			return fmt.Errorf("precompile upgrade %d is missing a block timestamp", i)
+		}
+	}
+
+	for _, address := range precompile.UsedAddresses {
+		configs := make([]precompile.StatefulPrecompileConfig, 0)
+		if config := c.genesisPrecompileConfig(address); config != nil {
+			configs = append(configs, config)
+		}
+		for _, upgrade := range c.PrecompileUpgrades {
+			if config := upgrade.getByAddress(address); config != nil {
+				configs = append(configs, config)
+			}
+		}
+
+		var (
+			lastTimestamp *big.Int
+			enabled       bool
+		)
+		for i, config := range configs {
+			if err := config.Verify(); err != nil {
+				return fmt.Errorf("invalid config %d for precompile %s: %w", i, address, err)
+			}
+			timestamp := config.Timestamp()
+			if lastTimestamp != nil && timestamp.Cmp(lastTimestamp) <= 0 {
+				return fmt.Errorf("config %d for precompile %s at timestamp %v must be after timestamp %v", i, address, timestamp, lastTimestamp)
+			}
+			if config.IsDisabled() && !enabled {
+				return fmt.Errorf("config %d for precompile %s at timestamp %v disables a precompile that is not enabled", i, address, timestamp)
+			}
+			// Only the genesis config may omit its timestamp, in which case it never takes effect.
+			enabled = !config.IsDisabled() && timestamp != nil
+			if timestamp != nil {
+				lastTimestamp = timestamp
+			}
+		}
+	}
+	return nil
+}
+
+// checkPrecompilesCompatible returns a compatibility error if any precompile config that took effect
+// at or before [headTimestamp] differs between [c] and [newcfg].
+func (c *ChainConfig) checkPrecompilesCompatible(newcfg *ChainConfig, headTimestamp *big.Int) *ConfigCompatError {
+	for _, address := range precompile.UsedAddresses {
+		storedConfigs := c.getActivatingPrecompileConfigs(address, nil, headTimestamp)
+		newConfigs := newcfg.getActivatingPrecompileConfigs(address, nil, headTimestamp)
+
+		for i := 0; i < len(storedConfigs) || i < len(newConfigs); i++ {
+			var storedTimestamp, newTimestamp *big.Int
+			if i < len(storedConfigs) {
+				storedTimestamp = storedConfigs[i].Timestamp()
+			}
+			if i < len(newConfigs) {
+				newTimestamp = newConfigs[i].Timestamp()
+			}
+			if i >= len(storedConfigs) || i >= len(newConfigs) || !storedConfigs[i].Equal(newConfigs[i]) {
+				return newCompatError(fmt.Sprintf("precompile %s upgrade timestamp", address), storedTimestamp, newTimestamp)
+			}
+		}
+	}
+	return nil
+}
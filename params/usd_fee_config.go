@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/gattaca-com/oracle-evm/utils"
+)
+
+// USDFeeConfig pegs the minimum base fee to USD. From [BlockTimestamp] onwards, the minimum base fee of
+// each block is converted to the native token using the AVAX/USD price of the price oracle in the state
+// of its parent. When no price is available, the minimum base fee of the fee config remains in effect.
+type USDFeeConfig struct {
+	BlockTimestamp *big.Int `json:"blockTimestamp"`
+
+	// MinBaseFee is the minimum base fee in attoUSD (10^-18 USD) per unit of gas.
+	MinBaseFee *big.Int `json:"minBaseFee"`
+
+	// MinBaseFeeFloor and MinBaseFeeCeiling bound the converted minimum base fee (in wei), so that an
+	// outlying price cannot make the network free or unusable.
+	MinBaseFeeFloor   *big.Int `json:"minBaseFeeFloor,omitempty"`
+	MinBaseFeeCeiling *big.Int `json:"minBaseFeeCeiling,omitempty"`
+
+	// SmoothingDenominator limits how fast a price drop can raise fees: the converted minimum base fee
+	// exceeds the base fee of the parent block by at most 1/SmoothingDenominator of it.
+	// If nil, the converted minimum base fee applies immediately.
+	SmoothingDenominator *big.Int `json:"smoothingDenominator,omitempty"`
+}
+
+// Verify returns an error if [c] is missing its activation timestamp or minimum base fee, or if its
+// bounds or smoothing are invalid.
+func (c *USDFeeConfig) Verify() error {
+	switch {
+	case c.BlockTimestamp == nil:
+		return fmt.Errorf("usd fee config must specify a block timestamp")
+	case c.MinBaseFee == nil || c.MinBaseFee.Sign() <= 0:
+		return fmt.Errorf("usd fee config minBaseFee must be positive, found: %v", c.MinBaseFee)
+	case c.MinBaseFeeFloor != nil && c.MinBaseFeeFloor.Sign() < 0:
+		return fmt.Errorf("usd fee config minBaseFeeFloor cannot be negative, found: %d", c.MinBaseFeeFloor)
+	case c.MinBaseFeeCeiling != nil && c.MinBaseFeeCeiling.Sign() <= 0:
+		return fmt.Errorf("usd fee config minBaseFeeCeiling must be positive, found: %d", c.MinBaseFeeCeiling)
+	case c.MinBaseFeeFloor != nil && c.MinBaseFeeCeiling != nil && c.MinBaseFeeFloor.Cmp(c.MinBaseFeeCeiling) > 0:
+		return fmt.Errorf("usd fee config minBaseFeeFloor (%d) cannot be greater than minBaseFeeCeiling (%d)", c.MinBaseFeeFloor, c.MinBaseFeeCeiling)
+	case c.SmoothingDenominator != nil && c.SmoothingDenominator.Sign() <= 0:
+		return fmt.Errorf("usd fee config smoothingDenominator must be positive, found: %d", c.SmoothingDenominator)
+	}
+	return nil
+}
+
+// Equal returns true iff [other] has the same activation and parameters as [c].
+func (c *USDFeeConfig) Equal(other *USDFeeConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return utils.BigNumEqual(c.BlockTimestamp, other.BlockTimestamp) &&
+		utils.BigNumEqual(c.MinBaseFee, other.MinBaseFee) &&
+		utils.BigNumEqual(c.MinBaseFeeFloor, other.MinBaseFeeFloor) &&
+		utils.BigNumEqual(c.MinBaseFeeCeiling, other.MinBaseFeeCeiling) &&
+		utils.BigNumEqual(c.SmoothingDenominator, other.SmoothingDenominator)
+}
+
+// timestamp returns the activation timestamp of [c], or nil if [c] is nil.
+func (c *USDFeeConfig) timestamp() *big.Int {
+	if c == nil {
+		return nil
+	}
+	return c.BlockTimestamp
+}
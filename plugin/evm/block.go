@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 
+	"github.com/gattaca-com/oracle-evm/core"
 	"github.com/gattaca-com/oracle-evm/core/types"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -111,8 +112,18 @@ func (b *Block) verifyPrices() error {
 		return fmt.Errorf("Block doesn not contain any prices")
 	}
 
-	return nil
-
+	// Typed values are not checked against the streamer, they must be valid values of typed
+	// feeds registered with the price oracle.
+	bc := b.vm.chain.BlockChain()
+	parent := bc.GetHeader(b.ethBlock.ParentHash(), b.ethBlock.NumberU64()-1)
+	if parent == nil {
+		return fmt.Errorf("parent %s of block %s not found", b.ethBlock.ParentHash().Hex(), b.ID())
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		return fmt.Errorf("could not get state of parent %s: %w", b.ethBlock.ParentHash().Hex(), err)
+	}
+	return core.VerifyFeedValues(b.vm.chainConfig, b.ethBlock, parent, statedb)
 }
 
 // Verify implements the snowman.Block interface
@@ -142,12 +142,16 @@ func (blockValidatorSubnetEVM) SyntacticVerify(b *Block) error {
 			ethHeader.Nonce.Uint64(), errInvalidNonce,
 		)
 	}
-	expectedGas := b.vm.chainConfig.GetFeeConfig().GasLimit.Uint64()
-	if ethHeader.GasLimit != expectedGas {
-		return fmt.Errorf(
-			"expected gas limit to be %d in subnetEVM but got %d",
-			expectedGas, ethHeader.GasLimit,
-		)
+	// Once the fee manager is enabled, the gas limit depends on the fee config stored in the state of the
+	// parent, so it is verified by the consensus engine instead.
+	if !b.vm.chainConfig.IsFeeConfigManager(new(big.Int).SetUint64(ethHeader.Time)) {
+		expectedGas := b.vm.chainConfig.GetFeeConfig().GasLimit.Uint64()
+		if ethHeader.GasLimit != expectedGas {
+			return fmt.Errorf(
+				"expected gas limit to be %d in subnetEVM but got %d",
+				expectedGas, ethHeader.GasLimit,
+			)
+		}
 	}
 	if ethHeader.MixDigest != (common.Hash{}) {
 		return fmt.Errorf(
@@ -29,6 +29,7 @@ const (
 	defaultOfflinePruningBloomFilterSize uint64 = 512 // Default size (MB) for the offline pruner to use
 	defaultLogLevel                             = "info"
 	defaultMaxOutboundActiveRequests            = 8
+	defaultFeedValuesTimeout                    = 500 * time.Millisecond
 )
 
 var defaultEnabledAPIs = []string{
@@ -39,6 +40,7 @@ var defaultEnabledAPIs = []string{
 	"internal-public-eth",
 	"internal-public-blockchain",
 	"internal-public-transaction-pool",
+	"internal-public-oracle",
 }
 
 type Duration struct {
@@ -107,6 +109,10 @@ type Config struct {
 
 	// VM2VM network
 	MaxOutboundActiveRequests int64 `json:"max-outbound-active-requests"`
+
+	// Oracle Settings
+	FeedValuesURL     string   `json:"feed-values-url"`     // If set, blocks built by this node carry the typed oracle values served at this URL
+	FeedValuesTimeout Duration `json:"feed-values-timeout"` // Maximum duration of a request for the typed oracle values
 }
 
 // EthAPIs returns an array of strings representing the Eth APIs that should be enabled
@@ -137,6 +143,7 @@ func (c *Config) SetDefaults() {
 	c.OfflinePruningBloomFilterSize = defaultOfflinePruningBloomFilterSize
 	c.LogLevel = defaultLogLevel
 	c.MaxOutboundActiveRequests = defaultMaxOutboundActiveRequests
+	c.FeedValuesTimeout.Duration = defaultFeedValuesTimeout
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gattaca-com/oracle-evm/core/types"
+)
+
+// maxFeedValuesSize is the maximum size of a response of the feed values URL.
+const maxFeedValuesSize = 1 << 20
+
+// newFeedValueSource returns a source of the typed oracle values carried by the blocks built by the VM,
+// which fetches them from [url]. The URL serves a JSON array of values, each with a "symbol", a hex
+// encoded 32 byte "value" and the "decimals" of the value, for instance
+// [{"symbol": "SOFR", "value": "0x...", "decimals": 4}].
+func newFeedValueSource(url string, timeout time.Duration) func() ([]*types.FeedValue, error) {
+	client := &http.Client{Timeout: timeout}
+	return func() ([]*types.FeedValue, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+		}
+		var values []*types.FeedValue
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxFeedValuesSize)).Decode(&values); err != nil {
+			return nil, fmt.Errorf("failed to decode feed values from %s: %w", url, err)
+		}
+		return values, nil
+	}
+}
@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedValueSource(t *testing.T) {
+	value := common.HexToHash("0x1234")
+	body := fmt.Sprintf(`[{"symbol": "SOFR", "value": "%s", "decimals": 4}, {"symbol": "POR/OK", "value": "%s"}]`, value.Hex(), common.BigToHash(common.Big1).Hex())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/values":
+			fmt.Fprint(w, body)
+		case "/malformed":
+			fmt.Fprint(w, `[{"symbol": "SOFR", "value": 5}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	values, err := newFeedValueSource(server.URL+"/values", time.Second)()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.FeedValue{
+		{Symbol: "SOFR", Value: value, Decimals: 4},
+		{Symbol: "POR/OK", Value: common.BigToHash(common.Big1)},
+	}, values)
+
+	_, err = newFeedValueSource(server.URL+"/malformed", time.Second)()
+	assert.Error(t, err)
+	_, err = newFeedValueSource(server.URL+"/missing", time.Second)()
+	assert.Error(t, err)
+}
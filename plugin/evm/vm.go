@@ -350,6 +350,11 @@ func (vm *VM) Initialize(
 		return err
 	}
 	vm.chain = ethChain
+	// Gattaca Mod. preview the prices of the next block in the pending block
+	vm.chain.SetPriceSource(vm.PythStreamer.GetPricesBytes)
+	if vm.config.FeedValuesURL != "" {
+		vm.chain.SetFeedValueSource(newFeedValueSource(vm.config.FeedValuesURL, vm.config.FeedValuesTimeout.Duration))
+	}
 	lastAccepted := vm.chain.LastAcceptedBlock()
 
 	// start goroutines to update the tx pool gas minimum gas price when upgrades go into effect
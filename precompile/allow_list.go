@@ -0,0 +1,237 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+// Enum constants for valid AllowListRole
+type AllowListRole common.Hash
+
+var (
+	AllowListNoRole  AllowListRole = AllowListRole(common.BigToHash(big.NewInt(0))) // No role assigned - this is equivalent to common.Hash{} and deletes the key from the DB when set
+	AllowListEnabled AllowListRole = AllowListRole(common.BigToHash(big.NewInt(1))) // Enabled - allowed to use the precompile
+	AllowListAdmin   AllowListRole = AllowListRole(common.BigToHash(big.NewInt(2))) // Admin - allowed to modify both the admin and enabled list as well as use the precompile
+
+	// AllowList function signatures
+	setAdminSignature      = CalculateFunctionSelector("setAdmin(address)")
+	setEnabledSignature    = CalculateFunctionSelector("setEnabled(address)")
+	setNoneSignature       = CalculateFunctionSelector("setNone(address)")
+	readAllowListSignature = CalculateFunctionSelector("readAllowList(address)")
+
+	// Error returned when an invalid write is attempted
+	ErrCannotModifyAllowList = errors.New("non-admin cannot modify allow list")
+
+	allowListInputLen = common.HashLength
+
+	// Roles are stored in slots derived from this prefix rather than directly under the address hash,
+	// so that they cannot collide with precompile specific slots such as the price feeds of the price oracle.
+	allowListRolePrefix = []byte("allowList.role")
+)
+
+// AllowListConfig specifies the initial set of allow list admins.
+type AllowListConfig struct {
+	AllowListAdmins []common.Address `json:"adminAddresses"`
+}
+
+// Configure initializes the address space of [precompileAddr] by initializing the role of each of
+// the addresses in [AllowListAdmins].
+func (c *AllowListConfig) Configure(state StateDB, precompileAddr common.Address) {
+	for _, adminAddr := range c.AllowListAdmins {
+		setAllowListRole(state, precompileAddr, adminAddr, AllowListAdmin)
+	}
+}
+
+// Verify returns an error if [c] lists the zero address or the same admin more than once.
+func (c *AllowListConfig) Verify() error {
+	admins := make(map[common.Address]struct{}, len(c.AllowListAdmins))
+	for _, admin := range c.AllowListAdmins {
+		if admin == (common.Address{}) {
+			return errors.New("allow list admin cannot be the zero address")
+		}
+		if _, exists := admins[admin]; exists {
+			return fmt.Errorf("duplicate allow list admin %s", admin)
+		}
+		admins[admin] = struct{}{}
+	}
+	return nil
+}
+
+// Equal returns true iff [other] has the same admins in the same order.
+func (c *AllowListConfig) Equal(other *AllowListConfig) bool {
+	if other == nil || len(c.AllowListAdmins) != len(other.AllowListAdmins) {
+		return false
+	}
+	for i, admin := range c.AllowListAdmins {
+		if admin != other.AllowListAdmins[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid returns true iff [s] represents a valid role.
+func (s AllowListRole) Valid() bool {
+	switch s {
+	case AllowListNoRole, AllowListEnabled, AllowListAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNoRole returns true if [s] indicates no specific role.
+func (s AllowListRole) IsNoRole() bool {
+	return s == AllowListNoRole
+}
+
+// IsAdmin returns true if [s] indicates the permission to modify the allow list.
+func (s AllowListRole) IsAdmin() bool {
+	return s == AllowListAdmin
+}
+
+// IsEnabled returns true if [s] indicates that it has permission to access the resource.
+func (s AllowListRole) IsEnabled() bool {
+	return s == AllowListAdmin || s == AllowListEnabled
+}
+
+// String returns a human readable name for [s].
+func (s AllowListRole) String() string {
+	switch s {
+	case AllowListNoRole:
+		return "NoRole"
+	case AllowListEnabled:
+		return "Enabled"
+	case AllowListAdmin:
+		return "Admin"
+	default:
+		return common.Hash(s).Hex()
+	}
+}
+
+// allowListRoleKey returns the storage slot holding the role of [address].
+func allowListRoleKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(allowListRolePrefix, address.Bytes())
+}
+
+// getAllowListStatus returns the allow list role of [address] for the precompile
+// at [precompileAddr]
+func getAllowListStatus(state StateDB, precompileAddr common.Address, address common.Address) AllowListRole {
+	return AllowListRole(state.GetState(precompileAddr, allowListRoleKey(address)))
+}
+
+// setAllowListRole sets the permissions of [address] to [role] for the precompile
+// at [precompileAddr].
+// assumes [role] has already been verified as valid.
+func setAllowListRole(stateDB StateDB, precompileAddr, address common.Address, role AllowListRole) {
+	stateDB.SetState(precompileAddr, allowListRoleKey(address), common.Hash(role))
+}
+
+// PackModifyAllowList packs [address] and [role] into the appropriate arguments for modifying the allow list.
+// Note: [role] is not packed in the input value returned, but is instead used as a selector for the function
+// selector that should be encoded in the input.
+func PackModifyAllowList(address common.Address, role AllowListRole) ([]byte, error) {
+	// function selector (4 bytes) + hash for address
+	input := make([]byte, 0, selectorLen+common.HashLength)
+
+	switch role {
+	case AllowListAdmin:
+		input = append(input, setAdminSignature...)
+	case AllowListEnabled:
+		input = append(input, setEnabledSignature...)
+	case AllowListNoRole:
+		input = append(input, setNoneSignature...)
+	default:
+		return nil, fmt.Errorf("cannot pack modify list input with invalid role: %s", role)
+	}
+
+	input = append(input, address.Hash().Bytes()...)
+	return input, nil
+}
+
+// PackReadAllowList packs [address] into the input data to the read allow list function
+func PackReadAllowList(address common.Address) []byte {
+	input := make([]byte, 0, selectorLen+common.HashLength)
+	input = append(input, readAllowListSignature...)
+	input = append(input, address.Hash().Bytes()...)
+	return input
+}
+
+// createAllowListRoleSetter returns an execution function for setting the allow list status of the input address argument to [role].
+// This execution function is specific to [precompileAddr].
+func createAllowListRoleSetter(precompileAddr common.Address, role AllowListRole) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, callerAddr, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, ModifyAllowListGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != allowListInputLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for modifying allow list: %d", len(input))
+		}
+
+		modifyAddress := common.BytesToAddress(input)
+
+		if readOnly {
+			return nil, remainingGas, vmerrs.ErrWriteProtection
+		}
+
+		stateDB := accessibleState.GetStateDB()
+
+		// Verify that the caller is in the allow list and therefore has the right to modify it
+		callerStatus := getAllowListStatus(stateDB, precompileAddr, callerAddr)
+		if !callerStatus.IsAdmin() {
+			return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotModifyAllowList, callerAddr)
+		}
+
+		setAllowListRole(stateDB, precompileAddr, modifyAddress, role)
+		// Return an empty output and the remaining gas
+		return []byte{}, remainingGas, nil
+	}
+}
+
+// createReadAllowList returns an execution function that reads the allow list for the given [precompileAddr].
+// The execution function parses the input into a single address and returns the 32 byte hash that specifies the
+// designated role of that address
+func createReadAllowList(precompileAddr common.Address) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, callerAddr common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = deductGas(suppliedGas, ReadAllowListGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		if len(input) != allowListInputLen {
+			return nil, remainingGas, fmt.Errorf("invalid input length for read allow list: %d", len(input))
+		}
+
+		readAddress := common.BytesToAddress(input)
+		role := getAllowListStatus(accessibleState.GetStateDB(), precompileAddr, readAddress)
+		roleBytes := common.Hash(role).Bytes()
+		return roleBytes, remainingGas, nil
+	}
+}
+
+// createAllowListPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr]
+func createAllowListPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
+	// Construct the contract with no fallback function.
+	allowListFuncs := createAllowListFunctions(precompileAddr)
+	contract := newStatefulPrecompileWithFunctionSelectors(nil, allowListFuncs)
+	return contract
+}
+
+// createAllowListFunctions returns the allow list functions of the precompile at [precompileAddr], so that
+// precompiles gated by an allow list can expose them alongside their own functions.
+func createAllowListFunctions(precompileAddr common.Address) []*statefulPrecompileFunction {
+	setAdmin := newStatefulPrecompileFunction(setAdminSignature, createAllowListRoleSetter(precompileAddr, AllowListAdmin))
+	setEnabled := newStatefulPrecompileFunction(setEnabledSignature, createAllowListRoleSetter(precompileAddr, AllowListEnabled))
+	setNone := newStatefulPrecompileFunction(setNoneSignature, createAllowListRoleSetter(precompileAddr, AllowListNoRole))
+	read := newStatefulPrecompileFunction(readAllowListSignature, createReadAllowList(precompileAddr))
+
+	return []*statefulPrecompileFunction{setAdmin, setEnabled, setNone, read}
+}
@@ -19,6 +19,24 @@ type RunStatefulPrecompileFunc func(accessibleState PrecompileAccessibleState, c
 // PrecompileAccessibleState defines the interface exposed to stateful precompile contracts
 type PrecompileAccessibleState interface {
 	GetStateDB() StateDB
+	GetBlockContext() BlockContext
+	// AddLog emits a log from [addr] in the block being processed. The log is reverted along
+	// with the rest of the state if the call fails.
+	AddLog(addr common.Address, topics []common.Hash, data []byte)
+}
+
+// BlockContext defines the information about the block being processed that is exposed to
+// stateful precompile contracts
+type BlockContext interface {
+	Number() *big.Int
+	Timestamp() *big.Int
+	// Coinbase returns the address receiving the fees of the block.
+	Coinbase() common.Address
+	// BaseFee returns the base fee of the block, or nil if the block has no base fee.
+	BaseFee() *big.Int
+	// Prices returns the latest prices of the streamed feeds written at the start of the block,
+	// or nil if they are not known.
+	Prices() PriceCache
 }
 
 // StateDB is the interface for accessing EVM state
@@ -37,6 +55,9 @@ type StateDB interface {
 
 	CreateAccount(common.Address)
 	Exist(common.Address) bool
+
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
 }
 
 // StatefulPrecompiledContract is the interface for executing a precompiled contract
@@ -0,0 +1,160 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+const (
+	mintInputLen = common.HashLength + common.HashLength
+)
+
+var (
+	_ StatefulPrecompileConfig = &ContractNativeMinterConfig{}
+	// Singleton StatefulPrecompiledContract for minting native assets by permissioned callers.
+	ContractNativeMinterPrecompile StatefulPrecompiledContract = createNativeMinterPrecompile(ContractNativeMinterAddress)
+
+	mintSignature = CalculateFunctionSelector("mintNativeCoin(address,uint256)") // address, amount
+
+	ErrCannotMint = errors.New("non-enabled cannot mint")
+)
+
+// ContractNativeMinterConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
+// interface while adding in the contract native minter specific precompile address.
+type ContractNativeMinterConfig struct {
+	AllowListConfig
+	UpgradeableConfig
+}
+
+// NewContractNativeMinterConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// ContractNativeMinter with the given [admins] as members of the allowlist.
+func NewContractNativeMinterConfig(blockTimestamp *big.Int, admins []common.Address) *ContractNativeMinterConfig {
+	return &ContractNativeMinterConfig{
+		AllowListConfig:   AllowListConfig{AllowListAdmins: admins},
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: blockTimestamp},
+	}
+}
+
+// NewDisableContractNativeMinterConfig returns config for a network upgrade at [blockTimestamp]
+// that disables ContractNativeMinter.
+func NewDisableContractNativeMinterConfig(blockTimestamp *big.Int) *ContractNativeMinterConfig {
+	return &ContractNativeMinterConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the native minter contract.
+func (c *ContractNativeMinterConfig) Address() common.Address {
+	return ContractNativeMinterAddress
+}
+
+// Configure configures [state] with the desired admins based on [c].
+func (c *ContractNativeMinterConfig) Configure(state StateDB, blockNumber *big.Int) {
+	c.AllowListConfig.Configure(state, ContractNativeMinterAddress)
+}
+
+// Contract returns the singleton stateful precompiled contract to be used for the native minter.
+func (c *ContractNativeMinterConfig) Contract() StatefulPrecompiledContract {
+	return ContractNativeMinterPrecompile
+}
+
+// Verify returns an error if the admins of [c] are invalid.
+func (c *ContractNativeMinterConfig) Verify() error {
+	return c.AllowListConfig.Verify()
+}
+
+// Equal returns true if [s] is a [*ContractNativeMinterConfig] and it has been configured identical to [c].
+func (c *ContractNativeMinterConfig) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*ContractNativeMinterConfig)
+	if !ok {
+		return false
+	}
+	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) && c.AllowListConfig.Equal(&other.AllowListConfig)
+}
+
+// GetContractNativeMinterStatus returns the role of [address] for the minter list.
+func GetContractNativeMinterStatus(stateDB StateDB, address common.Address) AllowListRole {
+	return getAllowListStatus(stateDB, ContractNativeMinterAddress, address)
+}
+
+// SetContractNativeMinterStatus sets the permissions of [address] to [role] for the
+// minter list. assumes [role] has already been verified as valid.
+func SetContractNativeMinterStatus(stateDB StateDB, address common.Address, role AllowListRole) {
+	setAllowListRole(stateDB, ContractNativeMinterAddress, address, role)
+}
+
+// PackMintInput packs [address] and [amount] into the appropriate arguments for minting operation.
+// Assumes that [amount] can be represented by 32 bytes.
+func PackMintInput(address common.Address, amount *big.Int) ([]byte, error) {
+	// function selector (4 bytes) + input(hash for address + hash for amount)
+	input := make([]byte, 0, selectorLen+mintInputLen)
+	input = append(input, mintSignature...)
+	input = append(input, address.Hash().Bytes()...)
+	input = append(input, common.BigToHash(amount).Bytes()...)
+	return input, nil
+}
+
+// UnpackMintInput attempts to unpack [input] into the arguments to the mint precompile
+// assumes that [input] does not include selector (omits first 4 bytes in PackMintInput)
+func UnpackMintInput(input []byte) (common.Address, *big.Int, error) {
+	if len(input) != mintInputLen {
+		return common.Address{}, nil, fmt.Errorf("invalid input length for minting: %d", len(input))
+	}
+	to := common.BytesToAddress(input[:common.HashLength])
+	assetAmount := new(big.Int).SetBytes(input[common.HashLength:mintInputLen])
+	return to, assetAmount, nil
+}
+
+// mintNativeCoin checks if the caller is permissioned for minting operation.
+// The execution function parses the [input] into native coin amount and receiver address.
+func mintNativeCoin(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, MintGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	to, amount, err := UnpackMintInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to mint
+	callerStatus := getAllowListStatus(stateDB, ContractNativeMinterAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotMint, caller)
+	}
+
+	// if there is no address in the state, create one.
+	if !stateDB.Exist(to) {
+		stateDB.CreateAccount(to)
+	}
+
+	stateDB.AddBalance(to, amount)
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
+// createNativeMinterPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr] and a native coin minter.
+func createNativeMinterPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
+	mint := newStatefulPrecompileFunction(mintSignature, mintNativeCoin)
+	functions := append(createAllowListFunctions(precompileAddr), mint)
+
+	// Construct the contract with no fallback function.
+	contract := newStatefulPrecompileWithFunctionSelectors(nil, functions)
+	return contract
+}
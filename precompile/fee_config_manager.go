@@ -0,0 +1,297 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/oracle-evm/commontype"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+const (
+	// Each field of the fee config is packed into, and stored in, its own 32 byte word.
+	numFeeConfigFields = 8
+	feeConfigInputLen  = numFeeConfigFields * common.HashLength
+)
+
+// Indices of the fee config fields, both in the ABI encoding and in storage.
+const (
+	gasLimitIndex = iota
+	targetBlockRateIndex
+	minBaseFeeIndex
+	targetGasIndex
+	baseFeeChangeDenominatorIndex
+	minBlockGasCostIndex
+	maxBlockGasCostIndex
+	blockGasCostStepIndex
+)
+
+var (
+	_ StatefulPrecompileConfig = &FeeConfigManagerConfig{}
+	// Singleton StatefulPrecompiledContract for setting the fee config by permissioned callers.
+	FeeConfigManagerPrecompile StatefulPrecompiledContract = createFeeConfigManagerPrecompile(FeeConfigManagerAddress)
+
+	setFeeConfigSignature = CalculateFunctionSelector("setFeeConfig(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)")
+	getFeeConfigSignature = CalculateFunctionSelector("getFeeConfig()")
+
+	ErrCannotChangeFee = errors.New("non-enabled cannot change fee config")
+
+	// The fee config is stored in slots derived from this prefix, so that it cannot collide with the allow list.
+	feeConfigKeyPrefix = []byte("feeConfigManager.feeConfig")
+)
+
+// FeeConfigManagerConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
+// interface while adding in the fee config manager specific precompile address and initial fee config.
+type FeeConfigManagerConfig struct {
+	AllowListConfig
+	UpgradeableConfig
+
+	// InitialFeeConfig is written to state whenever the precompile is configured, and replaces the
+	// fee config of the chain config from the block after activation onwards.
+	InitialFeeConfig *commontype.FeeConfig `json:"initialFeeConfig,omitempty"`
+}
+
+// NewFeeConfigManagerConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// FeeConfigManager with the given [admins] as members of the allowlist and [initialFeeConfig] as the fee config.
+func NewFeeConfigManagerConfig(blockTimestamp *big.Int, admins []common.Address, initialFeeConfig *commontype.FeeConfig) *FeeConfigManagerConfig {
+	return &FeeConfigManagerConfig{
+		AllowListConfig:   AllowListConfig{AllowListAdmins: admins},
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: blockTimestamp},
+		InitialFeeConfig:  initialFeeConfig,
+	}
+}
+
+// NewDisableFeeConfigManagerConfig returns config for a network upgrade at [blockTimestamp]
+// that disables FeeConfigManager.
+func NewDisableFeeConfigManagerConfig(blockTimestamp *big.Int) *FeeConfigManagerConfig {
+	return &FeeConfigManagerConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the fee config manager contract.
+func (c *FeeConfigManagerConfig) Address() common.Address {
+	return FeeConfigManagerAddress
+}
+
+// Configure configures [state] with the desired admins and initial fee config based on [c].
+func (c *FeeConfigManagerConfig) Configure(state StateDB, blockNumber *big.Int) {
+	c.AllowListConfig.Configure(state, FeeConfigManagerAddress)
+	if c.InitialFeeConfig != nil {
+		StoreFeeConfig(state, c.InitialFeeConfig)
+	}
+}
+
+// Contract returns the singleton stateful precompiled contract to be used for the fee config manager.
+func (c *FeeConfigManagerConfig) Contract() StatefulPrecompiledContract {
+	return FeeConfigManagerPrecompile
+}
+
+// Verify returns an error if the admins or the initial fee config of [c] are invalid.
+// Enabling the precompile requires an initial fee config, so that a fee config is always
+// present in state while the precompile is active.
+func (c *FeeConfigManagerConfig) Verify() error {
+	if err := c.AllowListConfig.Verify(); err != nil {
+		return err
+	}
+	if c.IsDisabled() {
+		return nil
+	}
+	if c.InitialFeeConfig == nil {
+		return errors.New("initial fee config must be specified")
+	}
+	if err := c.InitialFeeConfig.Verify(); err != nil {
+		return fmt.Errorf("invalid initial fee config: %w", err)
+	}
+	return nil
+}
+
+// Equal returns true if [s] is a [*FeeConfigManagerConfig] and it has been configured identical to [c].
+func (c *FeeConfigManagerConfig) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*FeeConfigManagerConfig)
+	if !ok {
+		return false
+	}
+	if !c.UpgradeableConfig.Equal(&other.UpgradeableConfig) || !c.AllowListConfig.Equal(&other.AllowListConfig) {
+		return false
+	}
+	if c.InitialFeeConfig == nil {
+		return other.InitialFeeConfig == nil
+	}
+	return c.InitialFeeConfig.Equal(other.InitialFeeConfig)
+}
+
+// GetFeeConfigManagerStatus returns the role of [address] for the fee config manager list.
+func GetFeeConfigManagerStatus(stateDB StateDB, address common.Address) AllowListRole {
+	return getAllowListStatus(stateDB, FeeConfigManagerAddress, address)
+}
+
+// SetFeeConfigManagerStatus sets the permissions of [address] to [role] for the
+// fee config manager list. assumes [role] has already been verified as valid.
+func SetFeeConfigManagerStatus(stateDB StateDB, address common.Address, role AllowListRole) {
+	setAllowListRole(stateDB, FeeConfigManagerAddress, address, role)
+}
+
+// feeConfigKey returns the storage slot holding the fee config field at [index].
+func feeConfigKey(index int) common.Hash {
+	return crypto.Keccak256Hash(feeConfigKeyPrefix, []byte{byte(index)})
+}
+
+// GetStoredFeeConfig returns the fee config stored in [stateDB], and false if no fee config has been stored.
+// A stored fee config always has a non-zero gas limit, so a zero gas limit means that nothing was stored.
+func GetStoredFeeConfig(stateDB StateDB) (*commontype.FeeConfig, bool) {
+	words := make([]common.Hash, numFeeConfigFields)
+	for i := range words {
+		words[i] = stateDB.GetState(FeeConfigManagerAddress, feeConfigKey(i))
+	}
+	feeConfig := feeConfigFromWords(words)
+	if feeConfig.GasLimit.Sign() == 0 {
+		return nil, false
+	}
+	return feeConfig, true
+}
+
+// StoreFeeConfig writes [feeConfig] to [stateDB].
+// assumes [feeConfig] has already been verified.
+func StoreFeeConfig(stateDB StateDB, feeConfig *commontype.FeeConfig) {
+	for i, word := range feeConfigToWords(feeConfig) {
+		stateDB.SetState(FeeConfigManagerAddress, feeConfigKey(i), word)
+	}
+}
+
+// feeConfigToWords returns the fields of [feeConfig] as 32 byte words in index order.
+func feeConfigToWords(feeConfig *commontype.FeeConfig) []common.Hash {
+	words := make([]common.Hash, numFeeConfigFields)
+	words[gasLimitIndex] = common.BigToHash(feeConfig.GasLimit)
+	words[targetBlockRateIndex] = common.BigToHash(new(big.Int).SetUint64(feeConfig.TargetBlockRate))
+	words[minBaseFeeIndex] = common.BigToHash(feeConfig.MinBaseFee)
+	words[targetGasIndex] = common.BigToHash(feeConfig.TargetGas)
+	words[baseFeeChangeDenominatorIndex] = common.BigToHash(feeConfig.BaseFeeChangeDenominator)
+	words[minBlockGasCostIndex] = common.BigToHash(feeConfig.MinBlockGasCost)
+	words[maxBlockGasCostIndex] = common.BigToHash(feeConfig.MaxBlockGasCost)
+	words[blockGasCostStepIndex] = common.BigToHash(feeConfig.BlockGasCostStep)
+	return words
+}
+
+// feeConfigFromWords returns the fee config held by [words] in index order.
+// The target block rate is truncated to 64 bits.
+func feeConfigFromWords(words []common.Hash) *commontype.FeeConfig {
+	return &commontype.FeeConfig{
+		GasLimit:                 words[gasLimitIndex].Big(),
+		TargetBlockRate:          words[targetBlockRateIndex].Big().Uint64(),
+		MinBaseFee:               words[minBaseFeeIndex].Big(),
+		TargetGas:                words[targetGasIndex].Big(),
+		BaseFeeChangeDenominator: words[baseFeeChangeDenominatorIndex].Big(),
+		MinBlockGasCost:          words[minBlockGasCostIndex].Big(),
+		MaxBlockGasCost:          words[maxBlockGasCostIndex].Big(),
+		BlockGasCostStep:         words[blockGasCostStepIndex].Big(),
+	}
+}
+
+// PackSetFeeConfig packs [feeConfig] into the input data to the set fee config function.
+func PackSetFeeConfig(feeConfig *commontype.FeeConfig) ([]byte, error) {
+	if err := feeConfig.Verify(); err != nil {
+		return nil, err
+	}
+	input := make([]byte, 0, selectorLen+feeConfigInputLen)
+	input = append(input, setFeeConfigSignature...)
+	for _, word := range feeConfigToWords(feeConfig) {
+		input = append(input, word.Bytes()...)
+	}
+	return input, nil
+}
+
+// UnpackFeeConfigInput attempts to unpack [input] into the arguments to the set fee config function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackSetFeeConfig)
+func UnpackFeeConfigInput(input []byte) (*commontype.FeeConfig, error) {
+	if len(input) != feeConfigInputLen {
+		return nil, fmt.Errorf("invalid input length for fee config: %d", len(input))
+	}
+	words := make([]common.Hash, numFeeConfigFields)
+	for i := range words {
+		words[i] = common.BytesToHash(input[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	if targetBlockRate := words[targetBlockRateIndex].Big(); !targetBlockRate.IsUint64() {
+		return nil, fmt.Errorf("targetBlockRate = %d cannot be larger than 64 bits", targetBlockRate)
+	}
+	return feeConfigFromWords(words), nil
+}
+
+// PackGetFeeConfigInput packs the input data to the get fee config function.
+func PackGetFeeConfigInput() []byte {
+	input := make([]byte, 0, selectorLen)
+	return append(input, getFeeConfigSignature...)
+}
+
+// setFeeConfig checks if the caller is permissioned to change the fee config.
+// The execution function parses the [input] into a fee config, verifies it and stores it in state.
+// The new fee config takes effect from the next block onwards.
+func setFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, SetFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	feeConfig, err := UnpackFeeConfigInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if err := feeConfig.Verify(); err != nil {
+		return nil, remainingGas, fmt.Errorf("invalid fee config: %w", err)
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to change the fee config
+	callerStatus := getAllowListStatus(stateDB, FeeConfigManagerAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrCannotChangeFee, caller)
+	}
+
+	StoreFeeConfig(stateDB, feeConfig)
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
+// getFeeConfig returns the fee config currently stored in state, packed as 8 words in the order of
+// the arguments to setFeeConfig.
+func getFeeConfig(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, GetFeeConfigGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if len(input) != 0 {
+		return nil, remainingGas, fmt.Errorf("invalid input length for get fee config: %d", len(input))
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	output := make([]byte, 0, feeConfigInputLen)
+	for i := 0; i < numFeeConfigFields; i++ {
+		output = append(output, stateDB.GetState(FeeConfigManagerAddress, feeConfigKey(i)).Bytes()...)
+	}
+	return output, remainingGas, nil
+}
+
+// createFeeConfigManagerPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr]
+// and functions to set and get the fee config.
+func createFeeConfigManagerPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
+	setFeeConfigFunc := newStatefulPrecompileFunction(setFeeConfigSignature, setFeeConfig)
+	getFeeConfigFunc := newStatefulPrecompileFunction(getFeeConfigSignature, getFeeConfig)
+	functions := append(createAllowListFunctions(precompileAddr), setFeeConfigFunc, getFeeConfigFunc)
+
+	// Construct the contract with no fallback function.
+	contract := newStatefulPrecompileWithFunctionSelectors(nil, functions)
+	return contract
+}
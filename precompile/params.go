@@ -7,10 +7,48 @@ import "github.com/ethereum/go-ethereum/common"
 
 // Gas costs for stateful precompiles
 const (
-	
+	writeGasCostPerSlot = 20_000
+	readGasCostPerSlot  = 5_000
+
+	ModifyAllowListGasCost = writeGasCostPerSlot
+	ReadAllowListGasCost   = readGasCostPerSlot
+
+	MintGasCost = 30_000
+
+	SetFeeConfigGasCost = writeGasCostPerSlot * numFeeConfigFields
+	GetFeeConfigGasCost = readGasCostPerSlot * numFeeConfigFields
 
 	GetPriceGasCost = 5_000
 
+	// setPrice reads the caller's role, the feed registry entry and the publisher's last update, and
+	// writes the price and the publisher's last update.
+	SetPriceGasCost = 3*readGasCostPerSlot + 2*writeGasCostPerSlot
+
+	// listFeeds reads the feed count and the id of each registered feed.
+	ListFeedsGasCost        = readGasCostPerSlot
+	ListFeedsGasCostPerFeed = readGasCostPerSlot
+	// getFeedInfo reads the feed registry entry and the price slot of the feed.
+	GetFeedInfoGasCost = 2 * readGasCostPerSlot
+	// getFeedIdBySymbol reads the registry index of the symbol and the id registered at that index.
+	GetFeedIdBySymbolGasCost = 2 * readGasCostPerSlot
+	// The typed value getters charge the price read cost for the feed, plus the value slot.
+	GetFeedValueGasCost = readGasCostPerSlot
+
+	// registerTrigger reads the feed registry entry and the active trigger count, and writes the fixed
+	// fields of the trigger, its position in the active list, the active trigger count and the next id.
+	// Each word of calldata and the gas limit of the callback are charged on top.
+	RegisterTriggerGasCost = 2*readGasCostPerSlot + 7*writeGasCostPerSlot
+	// cancelTrigger reads the trigger and the last active trigger, and clears the fixed fields of the trigger
+	// while moving the last active trigger into its position. Each word of calldata is charged on top.
+	CancelTriggerGasCost = 6*readGasCostPerSlot + 8*writeGasCostPerSlot
+	GetTriggerGasCost    = 4 * readGasCostPerSlot
+
+	// Reading a feed that has already been accessed in the current transaction skips the
+	// cold storage read, mirroring EIP-2929 (COLD_SLOAD_COST - WARM_STORAGE_READ_COST).
+	GetPriceWarmGasCost = GetPriceGasCost - (coldSloadCost - warmStorageReadCost)
+
+	coldSloadCost       = 2_100
+	warmStorageReadCost = 100
 )
 
 // Designated addresses of stateful precompiles
@@ -22,10 +60,19 @@ const (
 // that their own modifications do not conflict with stateful precompiles that may be added to subnet-evm
 // in the future.
 var (
-	
+	ContractDeployerAllowListAddress = common.HexToAddress("0x0200000000000000000000000000000000000000")
+	ContractNativeMinterAddress      = common.HexToAddress("0x0200000000000000000000000000000000000001")
+	TxAllowListAddress               = common.HexToAddress("0x0200000000000000000000000000000000000002")
+	FeeConfigManagerAddress          = common.HexToAddress("0x0200000000000000000000000000000000000003")
 	PriceOracleAddress               = common.HexToAddress("0x0300000000000000000000000000000000000001")
+	PriceTriggerAddress              = common.HexToAddress("0x0300000000000000000000000000000000000002")
 
 	UsedAddresses = []common.Address{
+		ContractDeployerAllowListAddress,
+		ContractNativeMinterAddress,
+		TxAllowListAddress,
+		FeeConfigManagerAddress,
 		PriceOracleAddress,
+		PriceTriggerAddress,
 	}
 )
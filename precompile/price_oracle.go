@@ -4,13 +4,15 @@
 package precompile
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gattaca-com/OraclePriceStreamer/streamer"
-	"github.com/gattaca-com/oracle-evm/vmerrs"
 )
 
 type PriceFeedId common.Hash
@@ -18,26 +20,33 @@ type PriceFeedId common.Hash
 var (
 	_ StatefulPrecompileConfig = &PriceOracleConfig{}
 	// Singleton StatefulPrecompiledContract for GetPriceing native assets by permissioned callers.
-	PriceOraclePreCompile StatefulPrecompiledContract = CreateNativeGetPriceerPrecompile(PriceOracleAddress)
+	PriceOraclePreCompile StatefulPrecompiledContract = priceOracleContractForSchedule(DefaultOracleGasSchedule)
 
 	// TODO perhaps put in a method to
 	getPriceSignature    = CalculateFunctionSelector("getPrice(uint256)")    // Hashed value of key (e.g. keccak256(btc/eth)) )
 	getDecimalsSignature = CalculateFunctionSelector("getDecimals(uint256)") // Hashed value of key (e.g. keccak256(btc/eth)) )
 
-	ErrCannotGetPrice = errors.New("non-enabled cannot GetPrice")
+	getPriceNoOlderThanSignature = CalculateFunctionSelector("getPriceNoOlderThan(uint256,uint64)") // feed id, max age in seconds
 
-	GetPriceInputLen = common.HashLength
-	SetPriceInputLen = common.HashLength + common.HashLength
+	ErrCannotGetPrice = errors.New("non-enabled cannot GetPrice")
+	ErrStalePrice     = errors.New("price is older than the max age")
+
+	GetPriceInputLen            = common.HashLength
+	GetPriceNoOlderThanInputLen = common.HashLength + common.HashLength
+	SetPriceInputLen            = common.HashLength + common.HashLength + common.HashLength
+
+	// The timestamp of the block in which the price of a feed was last updated is stored under
+	// [PriceOracleAddress] in a slot derived from this prefix, see [GetPriceUpdateTime].
+	priceUpdateTimePrefix = []byte("oracle.priceUpdateTime")
+	// The timestamp of the block in which the price of a feed was last written is stored under
+	// [PriceOracleAddress] in a slot derived from this prefix. It drives the heartbeat of the feed.
+	priceWriteTimePrefix = []byte("oracle.priceWriteTime")
 )
 
 var (
 	AVAX_USD = PriceFeedId(common.BigToHash(big.NewInt(0)))
 )
 
-var SymbolToFeedId = map[string]PriceFeedId{
-	"AVAX/USD": AVAX_USD,
-}
-
 func BytesToPriceFeedId(b []byte) PriceFeedId {
 	return PriceFeedId(common.BytesToHash(b))
 }
@@ -46,11 +55,75 @@ func (p *PriceFeedId) Bytes() []byte {
 	return common.Hash(*p).Bytes()
 }
 
+// errUnknownFeed returns the UnknownFeed(uint256 id) custom error for [id].
+func errUnknownFeed(id PriceFeedId) error {
+	return newRevertError(fmt.Errorf("%w: %s", ErrUnknownFeed, common.Hash(id).Hex()), "UnknownFeed(uint256)", id.Bytes())
+}
+
+// errStalePrice returns the StalePrice(uint256 id, uint64 updatedAt) custom error for [id] last written at [updated].
+func errStalePrice(id PriceFeedId, updated uint64) error {
+	err := fmt.Errorf("%w: %s was last updated at timestamp %d", ErrStalePrice, common.Hash(id).Hex(), updated)
+	return newRevertError(err, "StalePrice(uint256,uint64)", id.Bytes(), common.BigToHash(new(big.Int).SetUint64(updated)).Bytes())
+}
+
+// priceUpdateTimeKey returns the storage slot holding the timestamp at which the price of [id] was last updated.
+func priceUpdateTimeKey(id PriceFeedId) common.Hash {
+	return crypto.Keccak256Hash(priceUpdateTimePrefix, id.Bytes())
+}
+
+// priceWriteTimeKey returns the storage slot holding the timestamp at which the price of [id] was last written.
+func priceWriteTimeKey(id PriceFeedId) common.Hash {
+	return crypto.Keccak256Hash(priceWriteTimePrefix, id.Bytes())
+}
+
 // PriceOracleConfig wraps [AllowListConfig] and uses it to implement the StatefulPrecompileConfig
 // interface while adding in the contract deployer specific precompile address.
 type PriceOracleConfig struct {
-	BlockTimestamp  *big.Int         `json:"blockTimestamp"`
-	AllowListAdmins []common.Address `json:"adminAddresses"`
+	AllowListConfig
+	UpgradeableConfig
+
+	// InitialFeeds are registered, along with their initial prices, when the precompile is configured.
+	// Prices carried by block headers are only written to state for registered feeds.
+	InitialFeeds []OracleFeedConfig `json:"initialFeeds,omitempty"`
+
+	// GasSchedule overrides [DefaultOracleGasSchedule] while this config is in effect. The schedule
+	// is changed by a precompile upgrade that reconfigures the price oracle.
+	GasSchedule *OracleGasSchedule `json:"gasSchedule,omitempty"`
+
+	// MinPublishInterval is the minimum number of seconds between two updates of pushed feeds by the
+	// same publisher, whatever the feeds they update. Zero disables the rate limit.
+	MinPublishInterval uint64 `json:"minPublishInterval,omitempty"`
+	// Publishers override [MinPublishInterval] for the listed publishers.
+	Publishers []OraclePublisherConfig `json:"publishers,omitempty"`
+}
+
+// OraclePublisherConfig sets the rate limit of a publisher of pushed feeds.
+type OraclePublisherConfig struct {
+	Address common.Address `json:"address"`
+	// MinPublishInterval is the minimum number of seconds between two updates by [Address]. Zero
+	// disables the rate limit of [Address].
+	MinPublishInterval uint64 `json:"minPublishInterval"`
+}
+
+// NewPriceOracleConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// the price oracle with the given [admins] and registers [feeds].
+func NewPriceOracleConfig(blockTimestamp *big.Int, admins []common.Address, feeds []OracleFeedConfig) *PriceOracleConfig {
+	return &PriceOracleConfig{
+		AllowListConfig:   AllowListConfig{AllowListAdmins: admins},
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: blockTimestamp},
+		InitialFeeds:      feeds,
+	}
+}
+
+// NewDisablePriceOracleConfig returns a config for a network upgrade at [blockTimestamp]
+// that disables the price oracle.
+func NewDisablePriceOracleConfig(blockTimestamp *big.Int) *PriceOracleConfig {
+	return &PriceOracleConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
 }
 
 // Address returns the address of the native GetPriceer contract.
@@ -58,48 +131,183 @@ func (c *PriceOracleConfig) Address() common.Address {
 	return PriceOracleAddress
 }
 
-// Configure configures [state] with the desired admins based on [c].
-func (c *PriceOracleConfig) Configure(state StateDB) {
-	// DO configuration things in here
-	// TODO
-	if !state.Exist(c.Address()) {
-		state.CreateAccount(c.Address())
+// Configure configures [state] with the admins, initial feeds and publisher rate limits of [c].
+// The initial feeds are activated at [blockNumber]. When the oracle is reconfigured, feeds whose
+// id or symbol is already registered are left unchanged, as are the rate limits of publishers
+// that [c] does not list.
+func (c *PriceOracleConfig) Configure(state StateDB, blockNumber *big.Int) {
+	c.AllowListConfig.Configure(state, PriceOracleAddress)
+	// Initial prices are considered written at the activation of the precompile.
+	var activation uint64
+	if timestamp := c.Timestamp(); timestamp != nil {
+		activation = timestamp.Uint64()
+	}
+	for i := range c.InitialFeeds {
+		registerFeed(state, &c.InitialFeeds[i], blockNumber.Uint64(), activation)
+	}
+	setMinPublishInterval(state, c.MinPublishInterval)
+	for _, publisher := range c.Publishers {
+		setPublishInterval(state, publisher.Address, publisher.MinPublishInterval)
+	}
+}
+
+// Verify returns an error if the admins, initial feeds, publishers or gas schedule of [c] are invalid.
+func (c *PriceOracleConfig) Verify() error {
+	if err := c.AllowListConfig.Verify(); err != nil {
+		return fmt.Errorf("invalid price oracle allow list: %w", err)
+	}
+
+	ids := make(map[common.Hash]struct{}, len(c.InitialFeeds))
+	symbols := make(map[string]struct{}, len(c.InitialFeeds))
+	for i := range c.InitialFeeds {
+		feed := &c.InitialFeeds[i]
+		if err := feed.Verify(); err != nil {
+			return fmt.Errorf("invalid price oracle feed %d: %w", i, err)
+		}
+		if _, exists := ids[feed.Id]; exists {
+			return fmt.Errorf("duplicate price oracle feed id %s", feed.Id.Hex())
+		}
+		if _, exists := symbols[feed.Symbol]; exists {
+			return fmt.Errorf("duplicate price oracle feed symbol %q", feed.Symbol)
+		}
+		ids[feed.Id] = struct{}{}
+		symbols[feed.Symbol] = struct{}{}
 	}
 
-	sampleBtcAvaxVal := streamer.Price{
-		Price:    10000,
-		Slot:     12000,
-		Symbol:   "AVAX/USD",
-		Decimals: 8,
+	publishers := make(map[common.Address]struct{}, len(c.Publishers))
+	for _, publisher := range c.Publishers {
+		if _, exists := publishers[publisher.Address]; exists {
+			return fmt.Errorf("duplicate price oracle publisher %s", publisher.Address)
+		}
+		publishers[publisher.Address] = struct{}{}
 	}
 
-	state.SetState(c.Address(), common.BigToHash(big.NewInt(0)), streamer.PriceToHash(&sampleBtcAvaxVal))
+	return c.verifyGasSchedule()
 }
 
-// Contract returns the singleton stateful precompiled contract to be used for the native GetPriceer.
+// Contract returns the singleton stateful precompiled contract charging the gas schedule of [c].
 func (c *PriceOracleConfig) Contract() StatefulPrecompiledContract {
-	return PriceOraclePreCompile
+	return priceOracleContractForSchedule(c.gasSchedule())
 }
 
-// Contract returns the singleton stateful precompiled contract to be used for the native GetPriceer.
-func (c *PriceOracleConfig) Timestamp() *big.Int {
-	return big.NewInt(0)
-	// return big.NewInt(time.Now().AddDate(-1, 0, 0).UnixMilli())
-	// return c.BlockTimestamp
+// Equal returns true if [s] is a [*PriceOracleConfig] and it has been configured identical to [c].
+func (c *PriceOracleConfig) Equal(s StatefulPrecompileConfig) bool {
+	other, ok := (s).(*PriceOracleConfig)
+	if !ok {
+		return false
+	}
+	if !c.UpgradeableConfig.Equal(&other.UpgradeableConfig) || !c.AllowListConfig.Equal(&other.AllowListConfig) {
+		return false
+	}
+	if len(c.InitialFeeds) != len(other.InitialFeeds) || c.MinPublishInterval != other.MinPublishInterval {
+		return false
+	}
+	if len(c.Publishers) != len(other.Publishers) {
+		return false
+	}
+	for i, publisher := range c.Publishers {
+		if publisher != other.Publishers[i] {
+			return false
+		}
+	}
+	for i, feed := range c.InitialFeeds {
+		if !feed.Equal(&other.InitialFeeds[i]) {
+			return false
+		}
+	}
+	return c.gasScheduleEqual(other)
 }
 
-func WritePriceToState(state StateDB, price *streamer.Price) error {
+// GetPriceOracleAllowListStatus returns the role of [address] for the price oracle allow list.
+func GetPriceOracleAllowListStatus(stateDB StateDB, address common.Address) AllowListRole {
+	return getAllowListStatus(stateDB, PriceOracleAddress, address)
+}
+
+// SetPriceOracleAllowListStatus sets the permissions of [address] to [role] for the price oracle allow list.
+// assumes [role] has already been verified as valid.
+func SetPriceOracleAllowListStatus(stateDB StateDB, address common.Address, role AllowListRole) {
+	setAllowListRole(stateDB, PriceOracleAddress, address, role)
+}
+
+// WritePriceToState writes [price] to the slot of the feed registered for its symbol, as written by
+// the block at [timestamp], if the update policy of the feed requires it (see [FeedInfo.ShouldUpdate]).
+// Pushed feeds are only updated by their publishers, so header prices for them are ignored. Prices for
+// typed feeds are rejected, their values are written by WriteFeedValueToState. A price for AVAX/USD
+// written to an empty registry registers the legacy AVAX/USD feed first.
+func WritePriceToState(state StateDB, price *streamer.Price, timestamp uint64) error {
+	_, _, err := writePrice(state, price, timestamp)
+	return err
+}
+
+// writePrice writes [price] to state as WritePriceToState does and returns the id of the feed registered
+// for its symbol along with the latest price of the feed in state afterwards, or nil for a pushed feed.
+func writePrice(state StateDB, price *streamer.Price, timestamp uint64) (PriceFeedId, *streamer.Price, error) {
 
 	if !state.Exist(PriceOracleAddress) {
 		state.CreateAccount(PriceOracleAddress)
 	}
-
-	if priceFeedId, ok := SymbolToFeedId[price.Symbol]; ok {
-		state.SetState(PriceOracleAddress, common.Hash(priceFeedId), streamer.PriceToHash(price))
-		return nil
+	seedLegacyFeed(state, price.Symbol, timestamp)
+
+	if priceFeedId, ok := GetFeedIdBySymbol(state, price.Symbol); ok {
+		info, _ := GetFeedInfo(state, priceFeedId)
+		if info.Type != FeedTypePrice {
+			return priceFeedId, nil, fmt.Errorf("%w: %s publishes %s values", ErrWrongFeedType, price.Symbol, info.Type)
+		}
+		if info.Source == FeedSourcePushed {
+			return priceFeedId, nil, nil
+		}
+		if current, ok := ReadPriceFromState(state, priceFeedId); ok && !info.ShouldUpdate(current, price, getPriceWriteTime(state, priceFeedId), timestamp) {
+			// The stored price is still within the deviation of the header price, so it is as fresh as
+			// the header price: only its update time is refreshed.
+			setPriceUpdateTime(state, priceFeedId, timestamp)
+			return priceFeedId, current, nil
+		}
+		StorePrice(state, priceFeedId, price, timestamp)
+		// Read the price back so that it is exactly what later reads from state return.
+		stored, _ := ReadPriceFromState(state, priceFeedId)
+		return priceFeedId, stored, nil
 	}
 
-	return fmt.Errorf("Symbol id not currently supported to write. Key %s", price.Symbol)
+	return PriceFeedId{}, nil, fmt.Errorf("Symbol id not currently supported to write. Key %s", price.Symbol)
+}
+
+// StorePrice writes [price] as the latest price of [id] to [state], whatever the source of the feed,
+// and records [timestamp] as the time at which it was written.
+func StorePrice(state StateDB, id PriceFeedId, price *streamer.Price, timestamp uint64) {
+	state.SetState(PriceOracleAddress, common.Hash(id), streamer.PriceToHash(price))
+	state.SetState(PriceOracleAddress, priceWriteTimeKey(id), common.BigToHash(new(big.Int).SetUint64(timestamp)))
+	setPriceUpdateTime(state, id, timestamp)
+}
+
+// setPriceUpdateTime records [timestamp] as the time at which the price of [id] was last updated.
+func setPriceUpdateTime(state StateDB, id PriceFeedId, timestamp uint64) {
+	state.SetState(PriceOracleAddress, priceUpdateTimeKey(id), common.BigToHash(new(big.Int).SetUint64(timestamp)))
+}
+
+// GetPriceUpdateTime returns the timestamp of the latest block that wrote the price of [id], or whose
+// header carried a price for [id] that the update policy of the feed did not write because the stored
+// price had not deviated from it. It returns zero if the price of [id] was never written.
+func GetPriceUpdateTime(state StateDB, id PriceFeedId) uint64 {
+	return state.GetState(PriceOracleAddress, priceUpdateTimeKey(id)).Big().Uint64()
+}
+
+// getPriceWriteTime returns the timestamp of the block in which the price of [id] was last written,
+// or zero if it never was.
+func getPriceWriteTime(state StateDB, id PriceFeedId) uint64 {
+	return state.GetState(PriceOracleAddress, priceWriteTimeKey(id)).Big().Uint64()
+}
+
+// ReadPriceFromState returns the latest price of [id] in [state], or false if no price has been written for it.
+func ReadPriceFromState(state StateDB, id PriceFeedId) (*streamer.Price, bool) {
+	priceHash := state.GetState(PriceOracleAddress, common.Hash(id))
+	if priceHash == (common.Hash{}) {
+		return nil, false
+	}
+	price, err := streamer.UnmarshallPrice(priceHash.Bytes())
+	if err != nil {
+		return nil, false
+	}
+	return price, true
 }
 
 /*
@@ -127,63 +335,204 @@ func UnpackGetPriceInput(input []byte) (*PriceFeedId, error) {
 	return &identifier, nil
 }
 
-func getPriceStruct(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (price *streamer.Price, remainingGas uint64, err error) {
-	if remainingGas, err = deductGas(suppliedGas, GetPriceGasCost); err != nil {
-		return nil, 0, err
-	}
-
-	if readOnly {
-		return nil, remainingGas, vmerrs.ErrWriteProtection
-	}
-
+func getPriceStruct(accessibleState PrecompileAccessibleState, addr common.Address, input []byte, suppliedGas uint64, cost FunctionGasCost) (price *streamer.Price, remainingGas uint64, err error) {
 	identifier, err := UnpackGetPriceInput(input)
 	if err != nil {
-		return nil, remainingGas, err
+		return nil, suppliedGas, err
 	}
 
 	stateDB := accessibleState.GetStateDB()
+	if remainingGas, err = chargeFeedAccess(stateDB, addr, *identifier, cost, suppliedGas); err != nil {
+		return nil, 0, err
+	}
 
-	// if there is no address in the state, create one.
-	if !stateDB.Exist(addr) {
-		stateDB.CreateAccount(addr)
+	if priceStruct, ok := cachedPrice(accessibleState, addr, *identifier); ok {
+		return priceStruct, remainingGas, nil
+	}
+	// Typed feeds hold their type in the price slot, which does not decode as a price.
+	if info, ok := GetFeedInfo(stateDB, *identifier); ok && addr == PriceOracleAddress && info.Type != FeedTypePrice {
+		return nil, remainingGas, errWrongFeedType(info)
 	}
 
-	priceStructHash := stateDB.GetState(addr, common.Hash(*identifier))
-	priceStruct, _ := streamer.UnmarshallPrice(priceStructHash.Bytes())
+	priceStruct, ok := latestPrice(accessibleState, addr, *identifier)
+	if !ok {
+		// Feeds without a price read as the decoding of an empty slot.
+		priceStruct, _ = streamer.UnmarshallPrice(common.Hash{}.Bytes())
+	}
 
 	return priceStruct, remainingGas, nil
 }
 
-func getPrice(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+// createGetPrice returns the execution function for getPrice charging [cost].
+func createGetPrice(cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		priceStruct, remainingGas, err := getPriceStruct(accessibleState, addr, input, suppliedGas, cost)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		price := big.NewInt(priceStruct.Price)
+		// Return an empty output and the remaining gas
+		return common.BigToHash(price).Bytes(), remainingGas, nil
+	}
+}
 
-	priceStruct, remainingGas, err := getPriceStruct(accessibleState, caller, addr, input, suppliedGas, readOnly)
-	if err != nil {
-		return nil, remainingGas, err
+// createGetDecimals returns the execution function for getDecimals charging [cost].
+func createGetDecimals(cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		priceStruct, remainingGas, err := getPriceStruct(accessibleState, addr, input, suppliedGas, cost)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		decimals := big.NewInt(int64(priceStruct.Decimals))
+		// Return an empty output and the remaining gas
+		return common.BigToHash(decimals).Bytes(), remainingGas, nil
 	}
+}
 
-	price := big.NewInt(priceStruct.Price)
-	// Return an empty output and the remaining gas
-	return common.BigToHash(price).Bytes(), remainingGas, nil
+// readFeedPrice charges [cost] for reading [id] from [suppliedGas] and returns the latest price of [id].
+// It fails with UnknownFeed if [id] is not registered, with WrongFeedType if [id] is not a price feed and
+// with StalePrice if no price has been written for it.
+func readFeedPrice(accessibleState PrecompileAccessibleState, addr common.Address, id PriceFeedId, cost FunctionGasCost, suppliedGas uint64) (*streamer.Price, uint64, error) {
+	stateDB := accessibleState.GetStateDB()
+	remainingGas, err := chargeFeedAccess(stateDB, addr, id, cost, suppliedGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Cached feeds are registered, so the registry is only checked on a cache miss.
+	if price, ok := cachedPrice(accessibleState, addr, id); ok {
+		return price, remainingGas, nil
+	}
+	info, ok := GetFeedInfo(stateDB, id)
+	if !ok {
+		return nil, remainingGas, errUnknownFeed(id)
+	}
+	if info.Type != FeedTypePrice {
+		return nil, remainingGas, errWrongFeedType(info)
+	}
+	price, ok := latestPrice(accessibleState, addr, id)
+	if !ok {
+		return nil, remainingGas, errStalePrice(id, GetPriceUpdateTime(stateDB, id))
+	}
+	return price, remainingGas, nil
 }
 
-func getDecimals(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+// PackGetPriceNoOlderThanInput packs [identifier] and [maxAge] into the input data to the getPriceNoOlderThan function.
+func PackGetPriceNoOlderThanInput(identifier *PriceFeedId, maxAge uint64) ([]byte, error) {
+	input := make([]byte, 0, selectorLen+GetPriceNoOlderThanInputLen)
+	input = append(input, getPriceNoOlderThanSignature...)
+	input = append(input, identifier.Bytes()...)
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(maxAge)).Bytes()...)
+	return input, nil
+}
 
-	priceStruct, remainingGas, err := getPriceStruct(accessibleState, caller, addr, input, suppliedGas, readOnly)
-	if err != nil {
-		return nil, remainingGas, err
+// UnpackGetPriceNoOlderThanInput attempts to unpack [input] into the arguments to the getPriceNoOlderThan function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackGetPriceNoOlderThanInput)
+func UnpackGetPriceNoOlderThanInput(input []byte) (*PriceFeedId, uint64, error) {
+	if len(input) != GetPriceNoOlderThanInputLen {
+		return nil, 0, fmt.Errorf("invalid input length for getPriceNoOlderThan: %d", len(input))
 	}
+	identifier := BytesToPriceFeedId(input[:common.HashLength])
+	maxAge := new(big.Int).SetBytes(input[common.HashLength:])
+	if !maxAge.IsUint64() {
+		return nil, 0, fmt.Errorf("max age %d does not fit in 64 bits", maxAge)
+	}
+	return &identifier, maxAge.Uint64(), nil
+}
 
-	decimals := big.NewInt(int64(priceStruct.Decimals))
-	// Return an empty output and the remaining gas
-	return common.BigToHash(decimals).Bytes(), remainingGas, nil
+// createGetPriceNoOlderThan returns the execution function for getPriceNoOlderThan charging [cost].
+// The price of the feed is returned as an int256 if it was updated at most [maxAge] seconds before
+// the block being processed (see [GetPriceUpdateTime]), otherwise the call reverts with StalePrice.
+func createGetPriceNoOlderThan(cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		identifier, maxAge, err := UnpackGetPriceNoOlderThanInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+
+		price, remainingGas, err := readFeedPrice(accessibleState, addr, *identifier, cost, suppliedGas)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		updated := GetPriceUpdateTime(accessibleState.GetStateDB(), *identifier)
+		now := accessibleState.GetBlockContext().Timestamp().Uint64()
+		if now > updated && now-updated > maxAge {
+			return nil, remainingGas, errStalePrice(*identifier, updated)
+		}
+		return math.U256Bytes(big.NewInt(price.Price)), remainingGas, nil
+	}
 }
 
-// createNativeGetPriceerPrecompile returns a StatefulPrecompiledContract with R/W control of an allow list at [precompileAddr] and a native coin GetPriceer.
+// CreateNativeGetPriceerPrecompile returns the price oracle StatefulPrecompiledContract at [precompileAddr] charging [DefaultOracleGasSchedule].
 func CreateNativeGetPriceerPrecompile(precompileAddr common.Address) StatefulPrecompiledContract {
-	GetPrice := newStatefulPrecompileFunction(getPriceSignature, getPrice)
-	GetDecimals := newStatefulPrecompileFunction(getDecimalsSignature, getDecimals)
+	return CreatePriceOraclePrecompile(precompileAddr, DefaultOracleGasSchedule)
+}
+
+// priceOracleFunctions lists the functions of the price oracle by name. [takesFeed] is set for the
+// functions whose first argument is a feed id.
+var priceOracleFunctions = []struct {
+	name      string
+	selector  []byte
+	takesFeed bool
+}{
+	{"getPrice", getPriceSignature, true},
+	{"getDecimals", getDecimalsSignature, true},
+	{"getPriceNoOlderThan", getPriceNoOlderThanSignature, true},
+	{"getPriceScaled", getPriceScaledSignature, true},
+	{"convert", convertSignature, false},
+	{"listFeeds", listFeedsSignature, false},
+	{"getFeedInfo", getFeedInfoSignature, true},
+	{"getFeedIdBySymbol", getFeedIdBySymbolSignature, false},
+	{"getInt", getIntSignature, true},
+	{"getBytes32", getBytes32Signature, true},
+	{"getBool", getBoolSignature, true},
+	{"setPrice", setPriceSignature, true},
+	{"setAdmin", setAdminSignature, false},
+	{"setEnabled", setEnabledSignature, false},
+	{"setNone", setNoneSignature, false},
+	{"readAllowList", readAllowListSignature, false},
+}
+
+// PriceOracleFunction returns the name of the price oracle function selected by [input] and the
+// feed it accesses, or nil if the function does not take a feed. The name is empty if [input]
+// does not select a function of the price oracle.
+func PriceOracleFunction(input []byte) (string, *PriceFeedId) {
+	if len(input) < selectorLen {
+		return "", nil
+	}
+	for _, function := range priceOracleFunctions {
+		if !bytes.Equal(input[:selectorLen], function.selector) {
+			continue
+		}
+		if !function.takesFeed || len(input) < selectorLen+common.HashLength {
+			return function.name, nil
+		}
+		feed := BytesToPriceFeedId(input[selectorLen : selectorLen+common.HashLength])
+		return function.name, &feed
+	}
+	return "", nil
+}
+
+// CreatePriceOraclePrecompile returns the price oracle StatefulPrecompiledContract at [precompileAddr] charging [schedule].
+func CreatePriceOraclePrecompile(precompileAddr common.Address, schedule OracleGasSchedule) StatefulPrecompiledContract {
+	GetPrice := newStatefulPrecompileFunction(getPriceSignature, createGetPrice(schedule.GetPrice))
+	GetDecimals := newStatefulPrecompileFunction(getDecimalsSignature, createGetDecimals(schedule.GetDecimals))
+	GetPriceNoOlderThan := newStatefulPrecompileFunction(getPriceNoOlderThanSignature, createGetPriceNoOlderThan(schedule.GetPriceNoOlderThan))
+	GetPriceScaled := newStatefulPrecompileFunction(getPriceScaledSignature, createGetPriceScaled(schedule.GetPriceScaled))
+	Convert := newStatefulPrecompileFunction(convertSignature, createConvert(schedule.Convert))
+	ListFeeds := newStatefulPrecompileFunction(listFeedsSignature, listFeeds)
+	GetFeedInfo := newStatefulPrecompileFunction(getFeedInfoSignature, getFeedInfo)
+	GetFeedIdBySymbol := newStatefulPrecompileFunction(getFeedIdBySymbolSignature, getFeedIdBySymbol)
+	GetInt := newStatefulPrecompileFunction(getIntSignature, createGetFeedValue(FeedTypeInt256, schedule.GetInt))
+	GetBytes32 := newStatefulPrecompileFunction(getBytes32Signature, createGetFeedValue(FeedTypeBytes32, schedule.GetBytes32))
+	GetBool := newStatefulPrecompileFunction(getBoolSignature, createGetFeedValue(FeedTypeBool, schedule.GetBool))
+
+	SetPrice := newStatefulPrecompileFunction(setPriceSignature, setPrice)
+
+	functions := append(createAllowListFunctions(precompileAddr), GetPrice, GetDecimals, GetPriceNoOlderThan, GetPriceScaled, Convert, SetPrice, ListFeeds, GetFeedInfo, GetFeedIdBySymbol, GetInt, GetBytes32, GetBool)
 
 	// Construct the contract with no fallback function.
-	contract := newStatefulPrecompileWithFunctionSelectors(nil, []*statefulPrecompileFunction{GetPrice, GetDecimals})
+	contract := newStatefulPrecompileWithFunctionSelectors(nil, functions)
 	return contract
 }
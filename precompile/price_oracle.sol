@@ -1,16 +1,58 @@
-pragma solidity >=0.8.0;
+pragma solidity >=0.8.4;
 
 interface NativePriceOracleInterface {
-    
+
+    // Custom errors the oracle reverts with.
+    error UnknownFeed(uint256 identifier);
+    error StalePrice(uint256 identifier, uint64 updatedAt);
+    error NotPublisher(address caller);
+    error FeedNotPushed(uint256 identifier);
+    error InvalidPriceExpo(uint256 identifier, int32 expo);
+    error PublishRateLimited(uint256 identifier, uint64 nextPublishTime);
+    error ZeroPrice(uint256 identifier);
+    error ValueOverflow();
+    error UnknownSymbol(string symbol);
+    error WrongFeedType(uint256 identifier, uint8 feedType);
+
+    // Emitted when an allow listed publisher updates a pushed feed.
+    event PriceUpdated(uint256 indexed identifier, address indexed publisher, int256 price, int32 expo);
+
     function getPrice(uint256 identifier) external view returns (uint256);
 
     function getDecimals(uint256 identifier) external view returns (uint256);
-}
 
+    // Reverts if the price of the feed was last updated more than [maxAge] seconds before the current block.
+    // A header price that the update policy of the feed does not write, because it is within the deviation
+    // threshold of the stored price, still counts as an update.
+    function getPriceNoOlderThan(uint256 identifier, uint64 maxAge) external view returns (int256);
 
+    // Returns the price of the feed with [targetDecimals] decimals, rounded toward zero.
+    function getPriceScaled(uint256 identifier, uint8 targetDecimals) external view returns (int256);
 
+    // Returns the value of [amount] of the asset priced by [fromIdentifier] in the asset priced by [toIdentifier],
+    // with [outDecimals] more decimals than [amount], rounded toward zero. Both feeds must share the quote currency.
+    function convert(uint256 amount, uint256 fromIdentifier, uint256 toIdentifier, uint8 outDecimals) external view returns (int256);
 
+    // Returns the ids of all the registered feeds, in the order they were registered.
+    function listFeeds() external view returns (uint256[] memory);
 
+    // Returns the registry entry of a feed. [source] is 0 for feeds streamed in block headers and 1 for pushed
+    // feeds, [status] is 0 until the first price of the feed is written and 1 afterwards, [activationBlock]
+    // is the number of the block that registered the feed and [feedType] is 0 for price feeds, 1 for int256
+    // feeds, 2 for bytes32 feeds and 3 for bool feeds.
+    function getFeedInfo(uint256 identifier) external view returns (string memory symbol, uint8 source, uint16 decimals, uint8 status, uint64 activationBlock, uint8 feedType);
 
+    function getFeedIdBySymbol(string calldata symbol) external view returns (uint256);
 
+    // Return the latest value of a typed feed, reverting with WrongFeedType if the feed publishes another type.
+    function getInt(uint256 identifier) external view returns (int256 value, int32 expo);
 
+    function getBytes32(uint256 identifier) external view returns (bytes32);
+
+    function getBool(uint256 identifier) external view returns (bool);
+
+    // Sets the price of a pushed feed. [expo] must be the negated decimals of the feed. Each publisher is
+    // rate limited across all the feeds it publishes: it reverts with PublishRateLimited until the minimum
+    // publish interval of the caller has elapsed since its previous update of any feed.
+    function setPrice(uint256 identifier, int256 price, int32 expo) external;
+}
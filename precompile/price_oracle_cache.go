@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+)
+
+// PriceCache holds the latest prices of the streamed feeds, decoded once when the header prices are
+// written to state at the start of a block. Streamed feeds are only written by the header prices, so the
+// cached prices stay equal to the prices in state for the whole block and the price oracle serves reads
+// from the cache instead of decoding the price slots. Feeds missing from the cache are read from state.
+// The gas charged does not depend on whether a read is served from the cache.
+type PriceCache map[PriceFeedId]*streamer.Price
+
+// WriteHeaderPrices writes the header [prices] of the block at [timestamp] to [state] as
+// WritePriceToState does, and returns the cache of the latest prices of the streamed feeds they belong to.
+func WriteHeaderPrices(state StateDB, prices []*streamer.Price, timestamp uint64) PriceCache {
+	cache := make(PriceCache, len(prices))
+	for _, price := range prices {
+		id, latest, err := writePrice(state, price, timestamp)
+		if err != nil || latest == nil {
+			continue
+		}
+		cache[id] = latest
+	}
+	return cache
+}
+
+// cachedPrice returns the latest price of [id] stored at [addr] if the cache of the block holds it.
+func cachedPrice(accessibleState PrecompileAccessibleState, addr common.Address, id PriceFeedId) (*streamer.Price, bool) {
+	if addr != PriceOracleAddress {
+		return nil, false
+	}
+	price, ok := accessibleState.GetBlockContext().Prices()[id]
+	return price, ok
+}
+
+// latestPrice returns the latest price of [id] stored at [addr], or false if no price has been written for it.
+// The price is served from the cache of the block when it holds [id].
+func latestPrice(accessibleState PrecompileAccessibleState, addr common.Address, id PriceFeedId) (*streamer.Price, bool) {
+	if price, ok := cachedPrice(accessibleState, addr, id); ok {
+		return price, true
+	}
+	priceHash := accessibleState.GetStateDB().GetState(addr, common.Hash(id))
+	if priceHash == (common.Hash{}) {
+		return nil, false
+	}
+	price, err := streamer.UnmarshallPrice(priceHash.Bytes())
+	if err != nil {
+		return nil, false
+	}
+	return price, true
+}
@@ -0,0 +1,190 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+var (
+	listFeedsSignature         = CalculateFunctionSelector("listFeeds()")
+	getFeedInfoSignature       = CalculateFunctionSelector("getFeedInfo(uint256)")      // feed id
+	getFeedIdBySymbolSignature = CalculateFunctionSelector("getFeedIdBySymbol(string)") // symbol
+
+	ErrUnknownSymbol = errors.New("unknown price feed symbol")
+
+	GetFeedInfoInputLen = common.HashLength
+
+	// getFeedInfo returns (string symbol, uint8 source, uint16 decimals, uint8 status, uint64 activationBlock,
+	// uint8 feedType).
+	getFeedInfoHeadLen = 6 * common.HashLength
+)
+
+// errUnknownSymbol returns the UnknownSymbol(string symbol) custom error for [symbol].
+func errUnknownSymbol(symbol string) error {
+	offset := common.BigToHash(big.NewInt(common.HashLength)).Bytes()
+	return newRevertError(fmt.Errorf("%w: %q", ErrUnknownSymbol, symbol), "UnknownSymbol(string)", append(offset, packString(symbol)...))
+}
+
+// packString returns the ABI encoding of the contents of the string [s]: its length followed by [s]
+// right padded to a multiple of 32 bytes.
+func packString(s string) []byte {
+	paddedLen := (len(s) + common.HashLength - 1) / common.HashLength * common.HashLength
+	packed := common.BigToHash(big.NewInt(int64(len(s)))).Bytes()
+	return append(packed, common.RightPadBytes([]byte(s), paddedLen)...)
+}
+
+// unpackString returns the ABI encoded string whose offset is stored in the word of [data] at [offsetIndex].
+func unpackString(data []byte, offsetIndex int) (string, error) {
+	if len(data) < (offsetIndex+1)*common.HashLength {
+		return "", fmt.Errorf("invalid length for string offset: %d", len(data))
+	}
+	offset := new(big.Int).SetBytes(data[offsetIndex*common.HashLength : (offsetIndex+1)*common.HashLength])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-common.HashLength) {
+		return "", fmt.Errorf("invalid string offset: %d", offset)
+	}
+	start := offset.Uint64() + common.HashLength
+	length := new(big.Int).SetBytes(data[offset.Uint64():start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data))-start {
+		return "", fmt.Errorf("invalid length for string of %d bytes: %d", length, len(data))
+	}
+	return string(data[start : start+length.Uint64()]), nil
+}
+
+// PackListFeedsInput returns the input data to the listFeeds function.
+func PackListFeedsInput() []byte {
+	return append([]byte{}, listFeedsSignature...)
+}
+
+// UnpackListFeedsOutput attempts to unpack the ids of the feeds returned by the listFeeds function.
+func UnpackListFeedsOutput(output []byte) ([]PriceFeedId, error) {
+	if len(output) < 2*common.HashLength {
+		return nil, fmt.Errorf("invalid output length for listFeeds: %d", len(output))
+	}
+	count := new(big.Int).SetBytes(output[common.HashLength : 2*common.HashLength])
+	if !count.IsUint64() || count.Uint64() != uint64(len(output)/common.HashLength-2) {
+		return nil, fmt.Errorf("invalid feed count %d for output length %d", count, len(output))
+	}
+	ids := make([]PriceFeedId, count.Uint64())
+	for i := range ids {
+		ids[i] = BytesToPriceFeedId(output[(i+2)*common.HashLength : (i+3)*common.HashLength])
+	}
+	return ids, nil
+}
+
+// PackGetFeedInfoInput packs [identifier] into the input data to the getFeedInfo function.
+func PackGetFeedInfoInput(identifier *PriceFeedId) []byte {
+	return append(append([]byte{}, getFeedInfoSignature...), identifier.Bytes()...)
+}
+
+// UnpackGetFeedInfoOutput attempts to unpack the registry entry and status of [identifier] returned by
+// the getFeedInfo function.
+func UnpackGetFeedInfoOutput(identifier PriceFeedId, output []byte) (*FeedInfo, FeedStatus, error) {
+	if len(output) < getFeedInfoHeadLen {
+		return nil, 0, fmt.Errorf("invalid output length for getFeedInfo: %d", len(output))
+	}
+	word := func(i int) *big.Int {
+		return new(big.Int).SetBytes(output[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	symbol, err := unpackString(output, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, bits := range []int{8, 16, 8, 64, 8} {
+		if word(i+1).BitLen() > bits {
+			return nil, 0, fmt.Errorf("getFeedInfo output %d does not fit in %d bits", i+1, bits)
+		}
+	}
+	return &FeedInfo{
+		Id:              identifier,
+		Symbol:          symbol,
+		Source:          FeedSource(word(1).Uint64()),
+		Decimals:        uint16(word(2).Uint64()),
+		ActivationBlock: word(4).Uint64(),
+		Type:            FeedType(word(5).Uint64()),
+	}, FeedStatus(word(3).Uint64()), nil
+}
+
+// PackGetFeedIdBySymbolInput packs [symbol] into the input data to the getFeedIdBySymbol function.
+func PackGetFeedIdBySymbolInput(symbol string) []byte {
+	input := append([]byte{}, getFeedIdBySymbolSignature...)
+	input = append(input, common.BigToHash(big.NewInt(common.HashLength)).Bytes()...)
+	return append(input, packString(symbol)...)
+}
+
+// listFeeds returns the ids of all the registered feeds, in the order they were registered.
+func listFeeds(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, ListFeedsGasCost); err != nil {
+		return nil, 0, err
+	}
+	if len(input) != 0 {
+		return nil, remainingGas, fmt.Errorf("invalid input length for listFeeds: %d", len(input))
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	count := GetFeedCount(stateDB)
+	// Charge for reading the ids before reading them, so that the count alone bounds the work.
+	if count > remainingGas/ListFeedsGasCostPerFeed {
+		return nil, 0, vmerrs.ErrOutOfGas
+	}
+	remainingGas -= count * ListFeedsGasCostPerFeed
+
+	output := make([]byte, 0, (2+count)*common.HashLength)
+	output = append(output, common.BigToHash(big.NewInt(common.HashLength)).Bytes()...)
+	output = append(output, common.BigToHash(new(big.Int).SetUint64(count)).Bytes()...)
+	for index := uint64(0); index < count; index++ {
+		id := GetFeedIdAt(stateDB, index)
+		output = append(output, id.Bytes()...)
+	}
+	return output, remainingGas, nil
+}
+
+// getFeedInfo returns the registry entry and status of a feed, or reverts with UnknownFeed.
+func getFeedInfo(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, GetFeedInfoGasCost); err != nil {
+		return nil, 0, err
+	}
+	if len(input) != GetFeedInfoInputLen {
+		return nil, remainingGas, fmt.Errorf("invalid input length for getFeedInfo: %d", len(input))
+	}
+
+	identifier := BytesToPriceFeedId(input)
+	stateDB := accessibleState.GetStateDB()
+	info, ok := GetFeedInfo(stateDB, identifier)
+	if !ok {
+		return nil, remainingGas, errUnknownFeed(identifier)
+	}
+
+	output := make([]byte, 0, getFeedInfoHeadLen+2*common.HashLength)
+	output = append(output, common.BigToHash(big.NewInt(int64(getFeedInfoHeadLen))).Bytes()...)
+	output = append(output, common.BigToHash(big.NewInt(int64(info.Source))).Bytes()...)
+	output = append(output, common.BigToHash(big.NewInt(int64(info.Decimals))).Bytes()...)
+	output = append(output, common.BigToHash(big.NewInt(int64(GetFeedStatus(stateDB, identifier)))).Bytes()...)
+	output = append(output, common.BigToHash(new(big.Int).SetUint64(info.ActivationBlock)).Bytes()...)
+	output = append(output, common.BigToHash(big.NewInt(int64(info.Type))).Bytes()...)
+	output = append(output, packString(info.Symbol)...)
+	return output, remainingGas, nil
+}
+
+// getFeedIdBySymbol returns the id of the feed registered for a symbol, or reverts with UnknownSymbol.
+func getFeedIdBySymbol(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, GetFeedIdBySymbolGasCost); err != nil {
+		return nil, 0, err
+	}
+	symbol, err := unpackString(input, 0)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	id, ok := GetFeedIdBySymbol(accessibleState.GetStateDB(), symbol)
+	if !ok {
+		return nil, remainingGas, errUnknownSymbol(symbol)
+	}
+	return id.Bytes(), remainingGas, nil
+}
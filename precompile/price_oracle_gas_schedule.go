@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// DefaultOracleGasSchedule is used when a PriceOracleConfig does not specify a gas schedule.
+	DefaultOracleGasSchedule = OracleGasSchedule{
+		GetPrice:            defaultFeedReadGasCost,
+		GetDecimals:         defaultFeedReadGasCost,
+		GetPriceNoOlderThan: defaultFeedReadGasCost,
+		GetPriceScaled:      defaultFeedReadGasCost,
+		Convert:             defaultFeedReadGasCost,
+		GetInt:              defaultFeedReadGasCost,
+		GetBytes32:          defaultFeedReadGasCost,
+		GetBool:             defaultFeedReadGasCost,
+	}
+
+	defaultFeedReadGasCost = FunctionGasCost{Cold: GetPriceGasCost, Warm: GetPriceWarmGasCost}
+
+	// priceOracleContracts caches a StatefulPrecompiledContract per distinct gas schedule, so that
+	// a config charging a custom schedule does not rebuild the contract on every block.
+	priceOracleContracts sync.Map // OracleGasSchedule -> StatefulPrecompiledContract
+)
+
+// FunctionGasCost is the gas charged by a single oracle function.
+// [Cold] is charged the first time a feed is read within a transaction and [Warm] on any
+// later read of the same feed, following the EIP-2929 access list semantics.
+type FunctionGasCost struct {
+	Cold uint64 `json:"cold"`
+	Warm uint64 `json:"warm"`
+}
+
+// Verify returns an error if [c] charges more for a warm read than for a cold read.
+func (c FunctionGasCost) Verify() error {
+	if c.Warm > c.Cold {
+		return fmt.Errorf("warm gas cost (%d) cannot exceed cold gas cost (%d)", c.Warm, c.Cold)
+	}
+	return nil
+}
+
+// OracleGasSchedule defines the gas cost of each function exposed by the price oracle precompile
+// that reads feeds. Convert is charged its cost for each of its two feeds, and the typed value
+// getters are charged GetFeedValueGasCost on top of theirs. A function left out of a custom
+// schedule, with a zero cost, is charged its cost in DefaultOracleGasSchedule.
+type OracleGasSchedule struct {
+	GetPrice            FunctionGasCost `json:"getPrice"`
+	GetDecimals         FunctionGasCost `json:"getDecimals"`
+	GetPriceNoOlderThan FunctionGasCost `json:"getPriceNoOlderThan,omitempty"`
+	GetPriceScaled      FunctionGasCost `json:"getPriceScaled,omitempty"`
+	Convert             FunctionGasCost `json:"convert,omitempty"`
+	GetInt              FunctionGasCost `json:"getInt,omitempty"`
+	GetBytes32          FunctionGasCost `json:"getBytes32,omitempty"`
+	GetBool             FunctionGasCost `json:"getBool,omitempty"`
+}
+
+// scheduledFunction is a function of the price oracle and its cost in a gas schedule.
+type scheduledFunction struct {
+	name string
+	cost *FunctionGasCost
+}
+
+// functions returns the name and cost of each function in [s].
+func (s *OracleGasSchedule) functions() []scheduledFunction {
+	return []scheduledFunction{
+		{"getPrice", &s.GetPrice},
+		{"getDecimals", &s.GetDecimals},
+		{"getPriceNoOlderThan", &s.GetPriceNoOlderThan},
+		{"getPriceScaled", &s.GetPriceScaled},
+		{"convert", &s.Convert},
+		{"getInt", &s.GetInt},
+		{"getBytes32", &s.GetBytes32},
+		{"getBool", &s.GetBool},
+	}
+}
+
+// Verify returns an error if any of the function costs in [s] are invalid.
+func (s *OracleGasSchedule) Verify() error {
+	for _, function := range s.functions() {
+		if err := function.cost.Verify(); err != nil {
+			return fmt.Errorf("invalid gas cost for %s: %w", function.name, err)
+		}
+	}
+	return nil
+}
+
+// gasSchedule returns the gas schedule charged by the price oracle while [c] is in effect.
+func (c *PriceOracleConfig) gasSchedule() OracleGasSchedule {
+	if c.GasSchedule == nil {
+		return DefaultOracleGasSchedule
+	}
+	schedule, defaults := *c.GasSchedule, DefaultOracleGasSchedule
+	defaultFunctions := defaults.functions()
+	for i, function := range schedule.functions() {
+		if *function.cost == (FunctionGasCost{}) {
+			*function.cost = *defaultFunctions[i].cost
+		}
+	}
+	return schedule
+}
+
+// verifyGasSchedule returns an error if the gas schedule of [c] is invalid.
+func (c *PriceOracleConfig) verifyGasSchedule() error {
+	if c.GasSchedule == nil {
+		return nil
+	}
+	if err := c.GasSchedule.Verify(); err != nil {
+		return fmt.Errorf("invalid price oracle gas schedule: %w", err)
+	}
+	return nil
+}
+
+// gasScheduleEqual returns true if [c] and [other] charge the same gas schedule.
+func (c *PriceOracleConfig) gasScheduleEqual(other *PriceOracleConfig) bool {
+	if (c.GasSchedule == nil) != (other.GasSchedule == nil) {
+		return false
+	}
+	return c.GasSchedule == nil || *c.GasSchedule == *other.GasSchedule
+}
+
+// priceOracleContractForSchedule returns the cached contract for [schedule], creating it if necessary.
+func priceOracleContractForSchedule(schedule OracleGasSchedule) StatefulPrecompiledContract {
+	if contract, ok := priceOracleContracts.Load(schedule); ok {
+		return contract.(StatefulPrecompiledContract)
+	}
+	contract, _ := priceOracleContracts.LoadOrStore(schedule, CreatePriceOraclePrecompile(PriceOracleAddress, schedule))
+	return contract.(StatefulPrecompiledContract)
+}
+
+// chargeFeedAccess deducts the gas for reading [feedId] from [suppliedGas] according to [cost].
+// The feed's storage slot is added to the access list, so later reads of the same feed within
+// the transaction are charged the warm cost. Since the access list is journaled, a reverted call
+// also reverts the warming of the slot.
+func chargeFeedAccess(stateDB StateDB, addr common.Address, feedId PriceFeedId, cost FunctionGasCost, suppliedGas uint64) (uint64, error) {
+	slot := common.Hash(feedId)
+	if _, warm := stateDB.SlotInAccessList(addr, slot); warm {
+		return deductGas(suppliedGas, cost.Warm)
+	}
+	remainingGas, err := deductGas(suppliedGas, cost.Cold)
+	if err != nil {
+		return 0, err
+	}
+	stateDB.AddSlotToAccessList(addr, slot)
+	return remainingGas, nil
+}
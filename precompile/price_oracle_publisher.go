@@ -0,0 +1,192 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+var (
+	setPriceSignature = CalculateFunctionSelector("setPrice(uint256,int256,int32)") // feed id, price, expo
+
+	// PriceUpdatedEventTopic is the first topic of the log emitted by setPrice:
+	// PriceUpdated(uint256 indexed id, address indexed publisher, int256 price, int32 expo)
+	PriceUpdatedEventTopic = crypto.Keccak256Hash([]byte("PriceUpdated(uint256,address,int256,int32)"))
+
+	ErrCannotSetPrice     = errors.New("non-enabled cannot set price")
+	ErrUnknownFeed        = errors.New("unknown price feed")
+	ErrFeedNotPushed      = errors.New("price feed is not a pushed feed")
+	ErrInvalidPriceExpo   = errors.New("price expo does not match the feed decimals")
+	ErrPublishRateLimited = errors.New("publisher rate limit exceeded")
+
+	// The publisher rate limits are stored under [PriceOracleAddress] in slots derived from the following
+	// prefixes, so that they cannot collide with the price slots or the feed registry.
+	minPublishIntervalKey = crypto.Keccak256Hash([]byte("oracle.minPublishInterval"))
+	publishIntervalPrefix = []byte("oracle.publishInterval")
+	lastPublishPrefix     = []byte("oracle.lastPublish")
+)
+
+// errCannotSetPrice returns the NotPublisher(address caller) custom error for [caller].
+func errCannotSetPrice(caller common.Address) error {
+	return newRevertError(fmt.Errorf("%w: %s", ErrCannotSetPrice, caller), "NotPublisher(address)", caller.Hash().Bytes())
+}
+
+// errFeedNotPushed returns the FeedNotPushed(uint256 id) custom error for the feed [info].
+func errFeedNotPushed(info *FeedInfo) error {
+	return newRevertError(fmt.Errorf("%w: %s", ErrFeedNotPushed, info.Symbol), "FeedNotPushed(uint256)", info.Id.Bytes())
+}
+
+// errInvalidPriceExpo returns the InvalidPriceExpo(uint256 id, int32 expo) custom error for [expo] published for [info].
+func errInvalidPriceExpo(info *FeedInfo, expo int32) error {
+	err := fmt.Errorf("%w: expo %d for %s with %d decimals", ErrInvalidPriceExpo, expo, info.Symbol, info.Decimals)
+	return newRevertError(err, "InvalidPriceExpo(uint256,int32)", info.Id.Bytes(), math.U256Bytes(big.NewInt(int64(expo))))
+}
+
+// errPublishRateLimited returns the PublishRateLimited(uint256 id, uint64 nextPublishTime) custom error for
+// [caller] publishing [info] before [next].
+func errPublishRateLimited(caller common.Address, info *FeedInfo, next uint64) error {
+	err := fmt.Errorf("%w: %s can update %s from timestamp %d", ErrPublishRateLimited, caller, info.Symbol, next)
+	return newRevertError(err, "PublishRateLimited(uint256,uint64)", info.Id.Bytes(), common.BigToHash(new(big.Int).SetUint64(next)).Bytes())
+}
+
+// lastPublishKey returns the storage slot holding the timestamp at which [publisher] last updated a feed.
+func lastPublishKey(publisher common.Address) common.Hash {
+	return crypto.Keccak256Hash(lastPublishPrefix, publisher.Bytes())
+}
+
+// publishIntervalKey returns the storage slot holding the rate limit configured for [publisher].
+func publishIntervalKey(publisher common.Address) common.Hash {
+	return crypto.Keccak256Hash(publishIntervalPrefix, publisher.Bytes())
+}
+
+// GetMinPublishInterval returns the minimum number of seconds between two updates by the same publisher,
+// for publishers without a rate limit of their own.
+func GetMinPublishInterval(state StateDB) uint64 {
+	return state.GetState(PriceOracleAddress, minPublishIntervalKey).Big().Uint64()
+}
+
+func setMinPublishInterval(state StateDB, interval uint64) {
+	state.SetState(PriceOracleAddress, minPublishIntervalKey, common.BigToHash(new(big.Int).SetUint64(interval)))
+}
+
+// GetPublishInterval returns the minimum number of seconds between two updates by [publisher], whatever
+// the feeds it updates: the rate limit configured for [publisher] if any, or [GetMinPublishInterval].
+func GetPublishInterval(state StateDB, publisher common.Address) uint64 {
+	// [0] set flag, [24:32] interval.
+	packed := state.GetState(PriceOracleAddress, publishIntervalKey(publisher))
+	if packed[0] == 0 {
+		return GetMinPublishInterval(state)
+	}
+	return new(big.Int).SetBytes(packed[24:]).Uint64()
+}
+
+func setPublishInterval(state StateDB, publisher common.Address, interval uint64) {
+	packed := common.BigToHash(new(big.Int).SetUint64(interval))
+	packed[0] = 1
+	state.SetState(PriceOracleAddress, publishIntervalKey(publisher), packed)
+}
+
+// GetLastPublishTime returns the timestamp of the block in which [publisher] last updated a feed, or
+// zero if it never did.
+func GetLastPublishTime(state StateDB, publisher common.Address) uint64 {
+	return state.GetState(PriceOracleAddress, lastPublishKey(publisher)).Big().Uint64()
+}
+
+// PackSetPriceInput packs [identifier], [price] and [expo] into the input data to the set price function.
+func PackSetPriceInput(identifier *PriceFeedId, price int64, expo int32) ([]byte, error) {
+	input := make([]byte, 0, selectorLen+SetPriceInputLen)
+	input = append(input, setPriceSignature...)
+	input = append(input, identifier.Bytes()...)
+	input = append(input, math.U256Bytes(big.NewInt(price))...)
+	input = append(input, math.U256Bytes(big.NewInt(int64(expo)))...)
+	return input, nil
+}
+
+// UnpackSetPriceInput attempts to unpack [input] into the arguments to the set price function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackSetPriceInput)
+func UnpackSetPriceInput(input []byte) (*PriceFeedId, int64, int32, error) {
+	if len(input) != SetPriceInputLen {
+		return nil, 0, 0, fmt.Errorf("invalid input length for setting price: %d", len(input))
+	}
+	identifier := BytesToPriceFeedId(input[:common.HashLength])
+	price := math.S256(new(big.Int).SetBytes(input[common.HashLength : 2*common.HashLength]))
+	if !price.IsInt64() {
+		return nil, 0, 0, fmt.Errorf("price %d does not fit in 64 bits", price)
+	}
+	expo := math.S256(new(big.Int).SetBytes(input[2*common.HashLength : 3*common.HashLength]))
+	if !expo.IsInt64() || expo.Int64() < math.MinInt32 || expo.Int64() > math.MaxInt32 {
+		return nil, 0, 0, fmt.Errorf("expo %d does not fit in 32 bits", expo)
+	}
+	return &identifier, price.Int64(), int32(expo.Int64()), nil
+}
+
+// setPrice writes a price for a pushed feed. The caller must be enabled on the price oracle allow list,
+// [expo] must match the decimals the feed was registered with and the caller must respect its rate limit,
+// which spans all the feeds it publishes (see [GetPublishInterval]). The block number is stored as the
+// slot of the price.
+func setPrice(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, SetPriceGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	identifier, price, expo, err := UnpackSetPriceInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	// Verify that the caller is in the allow list and therefore has the right to publish prices
+	callerStatus := getAllowListStatus(stateDB, PriceOracleAddress, caller)
+	if !callerStatus.IsEnabled() {
+		return nil, remainingGas, errCannotSetPrice(caller)
+	}
+
+	info, ok := GetFeedInfo(stateDB, *identifier)
+	if !ok {
+		return nil, remainingGas, errUnknownFeed(*identifier)
+	}
+	if info.Source != FeedSourcePushed {
+		return nil, remainingGas, errFeedNotPushed(info)
+	}
+	if int64(expo) != -int64(info.Decimals) {
+		return nil, remainingGas, errInvalidPriceExpo(info, expo)
+	}
+
+	blockContext := accessibleState.GetBlockContext()
+	now := blockContext.Timestamp().Uint64()
+	if last := GetLastPublishTime(stateDB, caller); last != 0 {
+		if interval := GetPublishInterval(stateDB, caller); now < last+interval {
+			return nil, remainingGas, errPublishRateLimited(caller, info, last+interval)
+		}
+	}
+	stateDB.SetState(PriceOracleAddress, lastPublishKey(caller), common.BigToHash(new(big.Int).SetUint64(now)))
+
+	StorePrice(stateDB, *identifier, &streamer.Price{
+		Price:    price,
+		Slot:     blockContext.Number().Uint64(),
+		Symbol:   info.Symbol,
+		Decimals: uint(info.Decimals),
+	}, now)
+
+	topics := []common.Hash{PriceUpdatedEventTopic, common.Hash(*identifier), caller.Hash()}
+	data := make([]byte, 0, 2*common.HashLength)
+	data = append(data, math.U256Bytes(big.NewInt(price))...)
+	data = append(data, math.U256Bytes(big.NewInt(int64(expo)))...)
+	accessibleState.AddLog(PriceOracleAddress, topics, data)
+
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
@@ -0,0 +1,412 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/OraclePriceStreamer/streamer"
+)
+
+// MaxFeedSymbolLen is the longest symbol that fits alongside the price fields in a single storage slot
+// (see streamer.MarshallPrice).
+const MaxFeedSymbolLen = common.HashLength - (2 + 8 + 8 + 2)
+
+// feedSourceOffset is the byte of a packed registry entry holding the source of the feed.
+const feedSourceOffset = 4 + MaxFeedSymbolLen
+
+// feedActivationOffset is the first of the 8 bytes of a packed registry entry holding the activation block of the feed.
+const feedActivationOffset = feedSourceOffset + 1
+
+// feedDeviationOffset and feedHeartbeatOffset are the first of the 2 and 4 bytes of a packed registry entry
+// holding the update policy of the feed.
+const (
+	feedDeviationOffset = feedActivationOffset + 8
+	feedHeartbeatOffset = feedDeviationOffset + 2
+)
+
+// feedTypeOffset is the last byte of a packed registry entry, holding the type of the values of the feed.
+const feedTypeOffset = feedHeartbeatOffset + 4
+
+// basisPoints is the number of basis points in 100%.
+const basisPoints = 10_000
+
+// The feed registry is stored under [PriceOracleAddress] in slots derived from the following prefixes,
+// so that it cannot collide with the price slots, which are keyed directly by feed id.
+var (
+	feedCountKey     = crypto.Keccak256Hash([]byte("oracle.feedCount"))
+	feedIndexPrefix  = []byte("oracle.feedIndex")
+	feedSymbolPrefix = []byte("oracle.feedSymbol")
+	feedInfoPrefix   = []byte("oracle.feedInfo")
+)
+
+// legacyFeed is the only feed the price oracle served before feeds were registered from the chain config.
+// Chains that activated the price oracle before then have an empty registry, so it is registered when the
+// first header price for it is written (see seedLegacyFeed).
+var legacyFeed = OracleFeedConfig{Id: common.Hash(AVAX_USD), Symbol: "AVAX/USD", Decimals: 8}
+
+// FeedSource identifies where the prices of a feed come from.
+type FeedSource uint8
+
+const (
+	// FeedSourceStreamed feeds are updated from the prices carried by block headers.
+	FeedSourceStreamed FeedSource = iota
+	// FeedSourcePushed feeds are updated by allow listed publishers calling setPrice.
+	FeedSourcePushed
+)
+
+// Valid returns true iff [s] is a known feed source.
+func (s FeedSource) Valid() bool {
+	return s == FeedSourceStreamed || s == FeedSourcePushed
+}
+
+// String returns a human readable name for [s].
+func (s FeedSource) String() string {
+	switch s {
+	case FeedSourceStreamed:
+		return "streamed"
+	case FeedSourcePushed:
+		return "pushed"
+	default:
+		return fmt.Sprintf("FeedSource(%d)", uint8(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s FeedSource) MarshalText() ([]byte, error) {
+	if !s.Valid() {
+		return nil, fmt.Errorf("invalid feed source %d", uint8(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *FeedSource) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "streamed":
+		*s = FeedSourceStreamed
+	case "pushed":
+		*s = FeedSourcePushed
+	default:
+		return fmt.Errorf("unknown feed source %q", text)
+	}
+	return nil
+}
+
+// FeedType is the type of the values published by a feed.
+type FeedType uint8
+
+const (
+	// FeedTypePrice feeds publish prices carried by block headers as streamer prices, or set by publishers.
+	FeedTypePrice FeedType = iota
+	// FeedTypeInt256 feeds publish signed fixed point numbers, such as interest or funding rates.
+	FeedTypeInt256
+	// FeedTypeBytes32 feeds publish opaque 32 byte words, such as attestation hashes.
+	FeedTypeBytes32
+	// FeedTypeBool feeds publish flags, such as proof-of-reserve checks.
+	FeedTypeBool
+)
+
+// Valid returns true iff [t] is a known feed type.
+func (t FeedType) Valid() bool {
+	return t <= FeedTypeBool
+}
+
+// String returns a human readable name for [t].
+func (t FeedType) String() string {
+	switch t {
+	case FeedTypePrice:
+		return "price"
+	case FeedTypeInt256:
+		return "int256"
+	case FeedTypeBytes32:
+		return "bytes32"
+	case FeedTypeBool:
+		return "bool"
+	default:
+		return fmt.Sprintf("FeedType(%d)", uint8(t))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t FeedType) MarshalText() ([]byte, error) {
+	if !t.Valid() {
+		return nil, fmt.Errorf("invalid feed type %d", uint8(t))
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *FeedType) UnmarshalText(text []byte) error {
+	for candidate := FeedTypePrice; candidate.Valid(); candidate++ {
+		if string(text) == candidate.String() {
+			*t = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown feed type %q", text)
+}
+
+// OracleFeedConfig registers a feed with the price oracle when the precompile is configured.
+type OracleFeedConfig struct {
+	Id       common.Hash `json:"id"`
+	Symbol   string      `json:"symbol"`
+	Decimals uint16      `json:"decimals"`
+	// Source defaults to [FeedSourceStreamed].
+	Source FeedSource `json:"source,omitempty"`
+	// Type defaults to [FeedTypePrice]. Feeds of the other types are streamed from the typed values
+	// carried by block headers and have neither an update policy nor an initial price.
+	Type FeedType `json:"type,omitempty"`
+
+	// DeviationBps and Heartbeat set the update policy of a streamed feed, see [FeedInfo.ShouldUpdate].
+	// With both unset, every price carried by a block header is written to state.
+	DeviationBps uint16 `json:"deviationBps,omitempty"`
+	Heartbeat    uint32 `json:"heartbeat,omitempty"`
+
+	// InitialPrice is written to state when the feed is registered, so that the feed can be read
+	// before the first block carrying its price is accepted.
+	InitialPrice *OracleInitialPrice `json:"initialPrice,omitempty"`
+}
+
+// OracleInitialPrice is the price of a feed at the time the price oracle is configured.
+type OracleInitialPrice struct {
+	Price int64  `json:"price"`
+	Slot  uint64 `json:"slot"`
+}
+
+// FeedInfo is the registry entry of a feed.
+type FeedInfo struct {
+	Id       PriceFeedId
+	Symbol   string
+	Decimals uint16
+	Source   FeedSource
+	// ActivationBlock is the number of the block that registered the feed.
+	ActivationBlock uint64
+	// DeviationBps is the change, in basis points of the stored price, above which a header price is written.
+	DeviationBps uint16
+	// Heartbeat is the number of seconds after which a header price is written even if it did not deviate.
+	Heartbeat uint32
+	Type      FeedType
+}
+
+// ShouldUpdate returns true if [price], carried by the header of the block at [timestamp], must replace
+// [current], written at [updated], under the update policy of [f]: when it deviates from [current] by
+// more than [f.DeviationBps] basis points, or when at least [f.Heartbeat] seconds have passed since
+// [updated]. A zero heartbeat disables the heartbeat, and a feed without a policy is always updated.
+func (f *FeedInfo) ShouldUpdate(current *streamer.Price, price *streamer.Price, updated uint64, timestamp uint64) bool {
+	if f.DeviationBps == 0 && f.Heartbeat == 0 {
+		return true
+	}
+	if f.Heartbeat != 0 && timestamp >= updated+uint64(f.Heartbeat) {
+		return true
+	}
+	if current.Decimals != price.Decimals {
+		return true
+	}
+	// |price - current| * basisPoints > DeviationBps * |current|, computed exactly so that neither side overflows.
+	diff := new(big.Int).Sub(big.NewInt(price.Price), big.NewInt(current.Price))
+	diff.Abs(diff).Mul(diff, big.NewInt(basisPoints))
+	limit := new(big.Int).Abs(big.NewInt(current.Price))
+	limit.Mul(limit, big.NewInt(int64(f.DeviationBps)))
+	return diff.Cmp(limit) > 0
+}
+
+// FeedStatus is the status of a registered feed.
+type FeedStatus uint8
+
+const (
+	// FeedStatusPending feeds are registered but no price has been written for them yet.
+	FeedStatusPending FeedStatus = iota
+	// FeedStatusActive feeds have a price.
+	FeedStatusActive
+)
+
+// Verify returns an error if [c] cannot be registered.
+func (c *OracleFeedConfig) Verify() error {
+	if len(c.Symbol) == 0 {
+		return fmt.Errorf("feed %s is missing a symbol", c.Id.Hex())
+	}
+	if len(c.Symbol) > MaxFeedSymbolLen {
+		return fmt.Errorf("feed symbol %q exceeds the maximum length of %d bytes", c.Symbol, MaxFeedSymbolLen)
+	}
+	if !c.Source.Valid() {
+		return fmt.Errorf("feed %q has an invalid source %d", c.Symbol, uint8(c.Source))
+	}
+	if c.Source == FeedSourcePushed && (c.DeviationBps != 0 || c.Heartbeat != 0) {
+		return fmt.Errorf("pushed feed %q cannot have an update policy", c.Symbol)
+	}
+	if !c.Type.Valid() {
+		return fmt.Errorf("feed %q has an invalid type %d", c.Symbol, uint8(c.Type))
+	}
+	if c.Type != FeedTypePrice {
+		if c.Source != FeedSourceStreamed {
+			return fmt.Errorf("%s feed %q must be streamed", c.Type, c.Symbol)
+		}
+		if c.DeviationBps != 0 || c.Heartbeat != 0 || c.InitialPrice != nil {
+			return fmt.Errorf("%s feed %q cannot have an update policy or an initial price", c.Type, c.Symbol)
+		}
+	}
+	return nil
+}
+
+// Equal returns true if [other] registers the same feed with the same update policy and initial price as [c].
+func (c *OracleFeedConfig) Equal(other *OracleFeedConfig) bool {
+	if c.Id != other.Id || c.Symbol != other.Symbol || c.Decimals != other.Decimals || c.Source != other.Source || c.Type != other.Type {
+		return false
+	}
+	if c.DeviationBps != other.DeviationBps || c.Heartbeat != other.Heartbeat {
+		return false
+	}
+	if c.InitialPrice == nil || other.InitialPrice == nil {
+		return c.InitialPrice == nil && other.InitialPrice == nil
+	}
+	return *c.InitialPrice == *other.InitialPrice
+}
+
+// price returns the initial price of the feed described by [c].
+func (c *OracleFeedConfig) price() *streamer.Price {
+	return &streamer.Price{
+		Price:    c.InitialPrice.Price,
+		Slot:     c.InitialPrice.Slot,
+		Symbol:   c.Symbol,
+		Decimals: uint(c.Decimals),
+	}
+}
+
+func feedIndexKey(index uint64) common.Hash {
+	return crypto.Keccak256Hash(feedIndexPrefix, common.BigToHash(new(big.Int).SetUint64(index)).Bytes())
+}
+
+func feedSymbolKey(symbol string) common.Hash {
+	return crypto.Keccak256Hash(feedSymbolPrefix, []byte(symbol))
+}
+
+func feedInfoKey(id PriceFeedId) common.Hash {
+	return crypto.Keccak256Hash(feedInfoPrefix, id.Bytes())
+}
+
+// packFeedInfo packs [info] into a single storage slot:
+// [0] registered flag, [1:3] decimals, [3] symbol length, [4:4+MaxFeedSymbolLen] symbol, [feedSourceOffset] source,
+// [feedActivationOffset:feedActivationOffset+8] activation block, [feedDeviationOffset:feedDeviationOffset+2]
+// deviation threshold, [feedHeartbeatOffset:feedHeartbeatOffset+4] heartbeat, [feedTypeOffset] type.
+func packFeedInfo(info *FeedInfo) common.Hash {
+	var packed common.Hash
+	packed[0] = 1
+	binary.BigEndian.PutUint16(packed[1:3], info.Decimals)
+	packed[3] = byte(len(info.Symbol))
+	copy(packed[4:4+MaxFeedSymbolLen], info.Symbol)
+	packed[feedSourceOffset] = byte(info.Source)
+	binary.BigEndian.PutUint64(packed[feedActivationOffset:feedActivationOffset+8], info.ActivationBlock)
+	binary.BigEndian.PutUint16(packed[feedDeviationOffset:feedDeviationOffset+2], info.DeviationBps)
+	binary.BigEndian.PutUint32(packed[feedHeartbeatOffset:feedHeartbeatOffset+4], info.Heartbeat)
+	packed[feedTypeOffset] = byte(info.Type)
+	return packed
+}
+
+// unpackFeedInfo unpacks the registry entry for [id] from [packed]. Returns false if [packed] is
+// not a registry entry.
+func unpackFeedInfo(id PriceFeedId, packed common.Hash) (*FeedInfo, bool) {
+	if packed[0] == 0 {
+		return nil, false
+	}
+	symbolLen := int(packed[3])
+	if symbolLen > MaxFeedSymbolLen {
+		symbolLen = MaxFeedSymbolLen
+	}
+	return &FeedInfo{
+		Id:              id,
+		Symbol:          string(packed[4 : 4+symbolLen]),
+		Decimals:        binary.BigEndian.Uint16(packed[1:3]),
+		Source:          FeedSource(packed[feedSourceOffset]),
+		ActivationBlock: binary.BigEndian.Uint64(packed[feedActivationOffset : feedActivationOffset+8]),
+		DeviationBps:    binary.BigEndian.Uint16(packed[feedDeviationOffset : feedDeviationOffset+2]),
+		Heartbeat:       binary.BigEndian.Uint32(packed[feedHeartbeatOffset : feedHeartbeatOffset+4]),
+		Type:            FeedType(packed[feedTypeOffset]),
+	}, true
+}
+
+// GetFeedStatus returns the status of the registered feed [id].
+func GetFeedStatus(state StateDB, id PriceFeedId) FeedStatus {
+	if state.GetState(PriceOracleAddress, common.Hash(id)) == (common.Hash{}) {
+		return FeedStatusPending
+	}
+	return FeedStatusActive
+}
+
+// GetFeedCount returns the number of feeds registered with the price oracle.
+func GetFeedCount(state StateDB) uint64 {
+	count := state.GetState(PriceOracleAddress, feedCountKey).Big()
+	if !count.IsUint64() {
+		return math.MaxUint64
+	}
+	return count.Uint64()
+}
+
+// GetFeedIdAt returns the id of the feed registered at [index].
+func GetFeedIdAt(state StateDB, index uint64) PriceFeedId {
+	return PriceFeedId(state.GetState(PriceOracleAddress, feedIndexKey(index)))
+}
+
+// GetFeedInfo returns the registry entry of the feed [id] or false if [id] is not registered.
+func GetFeedInfo(state StateDB, id PriceFeedId) (*FeedInfo, bool) {
+	return unpackFeedInfo(id, state.GetState(PriceOracleAddress, feedInfoKey(id)))
+}
+
+// GetFeedIdBySymbol returns the id of the feed registered for [symbol] or false if there is none.
+func GetFeedIdBySymbol(state StateDB, symbol string) (PriceFeedId, bool) {
+	// The symbol maps to the registry index + 1, so that an unset slot means the symbol is not registered.
+	index := state.GetState(PriceOracleAddress, feedSymbolKey(symbol)).Big()
+	if index.Sign() == 0 || !index.IsUint64() {
+		return PriceFeedId{}, false
+	}
+	return GetFeedIdAt(state, index.Uint64()-1), true
+}
+
+// seedLegacyFeed registers the legacy feed if the registry is empty and [symbol] is the symbol of the
+// legacy feed, so that the header prices of chains activated before the registry keep being written to
+// the price slot they were written to before. The legacy feed is activated at genesis, where those chains
+// enabled the price oracle.
+func seedLegacyFeed(state StateDB, symbol string, timestamp uint64) {
+	if symbol == legacyFeed.Symbol && GetFeedCount(state) == 0 {
+		registerFeed(state, &legacyFeed, 0, timestamp)
+	}
+}
+
+// registerFeed adds [feed], activated at [timestamp], to the registry unless its id or symbol is already
+// registered, and writes its initial price, as written at [timestamp], if it has one. Returns false if the
+// feed was already registered.
+func registerFeed(state StateDB, feed *OracleFeedConfig, blockNumber uint64, timestamp uint64) bool {
+	id := PriceFeedId(feed.Id)
+	if _, exists := GetFeedInfo(state, id); exists {
+		return false
+	}
+	if _, exists := GetFeedIdBySymbol(state, feed.Symbol); exists {
+		return false
+	}
+
+	index := GetFeedCount(state)
+	state.SetState(PriceOracleAddress, feedIndexKey(index), common.Hash(id))
+	state.SetState(PriceOracleAddress, feedSymbolKey(feed.Symbol), common.BigToHash(new(big.Int).SetUint64(index+1)))
+	state.SetState(PriceOracleAddress, feedInfoKey(id), packFeedInfo(&FeedInfo{
+		Id:              id,
+		Symbol:          feed.Symbol,
+		Decimals:        feed.Decimals,
+		Source:          feed.Source,
+		ActivationBlock: blockNumber,
+		DeviationBps:    feed.DeviationBps,
+		Heartbeat:       feed.Heartbeat,
+		Type:            feed.Type,
+	}))
+	state.SetState(PriceOracleAddress, feedCountKey, common.BigToHash(new(big.Int).SetUint64(index+1)))
+
+	if feed.InitialPrice != nil {
+		StorePrice(state, id, feed.price(), timestamp)
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var (
+	getPriceScaledSignature = CalculateFunctionSelector("getPriceScaled(uint256,uint8)")          // feed id, target decimals
+	convertSignature        = CalculateFunctionSelector("convert(uint256,uint256,uint256,uint8)") // amount, from feed id, to feed id, out decimals
+
+	ErrZeroPrice     = errors.New("price of the feed is zero")
+	ErrValueOverflow = errors.New("scaled value does not fit in an int256")
+
+	GetPriceScaledInputLen = common.HashLength + common.HashLength
+	ConvertInputLen        = 4 * common.HashLength
+
+	big10 = big.NewInt(10)
+)
+
+// errZeroPrice returns the ZeroPrice(uint256 id) custom error for [id].
+func errZeroPrice(id PriceFeedId) error {
+	return newRevertError(fmt.Errorf("%w: %s", ErrZeroPrice, common.Hash(id).Hex()), "ZeroPrice(uint256)", id.Bytes())
+}
+
+// errValueOverflow returns the ValueOverflow() custom error.
+func errValueOverflow() error {
+	return newRevertError(ErrValueOverflow, "ValueOverflow()")
+}
+
+// pow10 returns 10^[n].
+func pow10(n uint64) *big.Int {
+	return new(big.Int).Exp(big10, new(big.Int).SetUint64(n), nil)
+}
+
+// packInt256 returns the ABI encoding of [value] as an int256, or ValueOverflow if it does not fit.
+func packInt256(value *big.Int) ([]byte, error) {
+	if value.BitLen() > 255 {
+		return nil, errValueOverflow()
+	}
+	return math.U256Bytes(new(big.Int).Set(value)), nil
+}
+
+// unpackUint8 returns the uint8 ABI encoded in [word].
+func unpackUint8(word []byte) (uint8, error) {
+	value := new(big.Int).SetBytes(word)
+	if !value.IsUint64() || value.Uint64() > math.MaxUint8 {
+		return 0, fmt.Errorf("value %d does not fit in 8 bits", value)
+	}
+	return uint8(value.Uint64()), nil
+}
+
+// PackGetPriceScaledInput packs [identifier] and [targetDecimals] into the input data to the getPriceScaled function.
+func PackGetPriceScaledInput(identifier *PriceFeedId, targetDecimals uint8) ([]byte, error) {
+	input := make([]byte, 0, selectorLen+GetPriceScaledInputLen)
+	input = append(input, getPriceScaledSignature...)
+	input = append(input, identifier.Bytes()...)
+	input = append(input, common.BigToHash(big.NewInt(int64(targetDecimals))).Bytes()...)
+	return input, nil
+}
+
+// UnpackGetPriceScaledInput attempts to unpack [input] into the arguments to the getPriceScaled function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackGetPriceScaledInput)
+func UnpackGetPriceScaledInput(input []byte) (*PriceFeedId, uint8, error) {
+	if len(input) != GetPriceScaledInputLen {
+		return nil, 0, fmt.Errorf("invalid input length for getPriceScaled: %d", len(input))
+	}
+	identifier := BytesToPriceFeedId(input[:common.HashLength])
+	targetDecimals, err := unpackUint8(input[common.HashLength:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid target decimals: %w", err)
+	}
+	return &identifier, targetDecimals, nil
+}
+
+// PackConvertInput packs [amount], [fromFeed], [toFeed] and [outDecimals] into the input data to the convert function.
+func PackConvertInput(amount *big.Int, fromFeed *PriceFeedId, toFeed *PriceFeedId, outDecimals uint8) ([]byte, error) {
+	if amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, fmt.Errorf("amount %d is not a uint256", amount)
+	}
+	input := make([]byte, 0, selectorLen+ConvertInputLen)
+	input = append(input, convertSignature...)
+	input = append(input, common.BigToHash(amount).Bytes()...)
+	input = append(input, fromFeed.Bytes()...)
+	input = append(input, toFeed.Bytes()...)
+	input = append(input, common.BigToHash(big.NewInt(int64(outDecimals))).Bytes()...)
+	return input, nil
+}
+
+// UnpackConvertInput attempts to unpack [input] into the arguments to the convert function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackConvertInput)
+func UnpackConvertInput(input []byte) (*big.Int, *PriceFeedId, *PriceFeedId, uint8, error) {
+	if len(input) != ConvertInputLen {
+		return nil, nil, nil, 0, fmt.Errorf("invalid input length for convert: %d", len(input))
+	}
+	amount := new(big.Int).SetBytes(input[:common.HashLength])
+	fromFeed := BytesToPriceFeedId(input[common.HashLength : 2*common.HashLength])
+	toFeed := BytesToPriceFeedId(input[2*common.HashLength : 3*common.HashLength])
+	outDecimals, err := unpackUint8(input[3*common.HashLength:])
+	if err != nil {
+		return nil, nil, nil, 0, fmt.Errorf("invalid out decimals: %w", err)
+	}
+	return amount, &fromFeed, &toFeed, outDecimals, nil
+}
+
+// ScalePrice returns [price] as a fixed point number with [targetDecimals] decimals, rounded toward zero.
+func ScalePrice(price int64, decimals uint, targetDecimals uint8) *big.Int {
+	scaled := new(big.Int).Mul(big.NewInt(price), pow10(uint64(targetDecimals)))
+	return scaled.Quo(scaled, pow10(uint64(decimals)))
+}
+
+// ConvertAmount returns the value of [amount] units of the asset priced by [from] in units of the asset
+// priced by [to], both quoted in the same currency, with [outDecimals] more decimals than [amount].
+// The result is computed with a single division, rounded toward zero. [to] must not be zero.
+func ConvertAmount(amount *big.Int, from, to *big.Int, fromDecimals, toDecimals uint, outDecimals uint8) *big.Int {
+	// amount * (from / 10^fromDecimals) / (to / 10^toDecimals) * 10^outDecimals
+	num := new(big.Int).Mul(amount, from)
+	num.Mul(num, pow10(uint64(outDecimals)+uint64(toDecimals)))
+	den := new(big.Int).Mul(to, pow10(uint64(fromDecimals)))
+	return num.Quo(num, den)
+}
+
+// createGetPriceScaled returns the execution function for getPriceScaled charging [cost].
+// The latest price of the feed is returned as an int256 with [targetDecimals] decimals.
+func createGetPriceScaled(cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		identifier, targetDecimals, err := UnpackGetPriceScaledInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+
+		price, remainingGas, err := readFeedPrice(accessibleState, addr, *identifier, cost, suppliedGas)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		ret, err = packInt256(ScalePrice(price.Price, price.Decimals, targetDecimals))
+		return ret, remainingGas, err
+	}
+}
+
+// createConvert returns the execution function for convert charging [cost] for each of the two feeds.
+// The value of the amount in the asset of the from feed is returned as an int256 in the asset of the
+// to feed. The call reverts with ZeroPrice if the price of the to feed is zero.
+func createConvert(cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		amount, fromFeed, toFeed, outDecimals, err := UnpackConvertInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+
+		from, remainingGas, err := readFeedPrice(accessibleState, addr, *fromFeed, cost, suppliedGas)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		to, remainingGas, err := readFeedPrice(accessibleState, addr, *toFeed, cost, remainingGas)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if to.Price == 0 {
+			return nil, remainingGas, errZeroPrice(*toFeed)
+		}
+		value := ConvertAmount(amount, big.NewInt(from.Price), big.NewInt(to.Price), from.Decimals, to.Decimals, outDecimals)
+		ret, err = packInt256(value)
+		return ret, remainingGas, err
+	}
+}
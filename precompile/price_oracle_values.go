@@ -0,0 +1,207 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	getIntSignature     = CalculateFunctionSelector("getInt(uint256)")     // feed id
+	getBytes32Signature = CalculateFunctionSelector("getBytes32(uint256)") // feed id
+	getBoolSignature    = CalculateFunctionSelector("getBool(uint256)")    // feed id
+
+	ErrWrongFeedType    = errors.New("feed does not publish values of the requested type")
+	ErrInvalidFeedValue = errors.New("invalid value for the type of the feed")
+
+	GetFeedValueInputLen = common.HashLength
+
+	// The value of a typed feed is stored in a slot derived from this prefix, while the price slot of the
+	// feed holds its type and decimals, so that the status of the feed is derived the same way for all types.
+	feedValuePrefix = []byte("oracle.feedValue")
+)
+
+// errWrongFeedType returns the WrongFeedType(uint256 id, uint8 feedType) custom error for [info].
+func errWrongFeedType(info *FeedInfo) error {
+	err := fmt.Errorf("%w: %s publishes %s values", ErrWrongFeedType, common.Hash(info.Id).Hex(), info.Type)
+	return newRevertError(err, "WrongFeedType(uint256,uint8)", info.Id.Bytes(), common.BigToHash(big.NewInt(int64(info.Type))).Bytes())
+}
+
+func feedValueKey(id PriceFeedId) common.Hash {
+	return crypto.Keccak256Hash(feedValuePrefix, id.Bytes())
+}
+
+// FeedValue is the latest value of a feed of a type other than [FeedTypePrice]. Depending on [Type],
+// [Value] holds a two's complement int256 with [Decimals] decimals, an opaque word or a bool (0 or 1).
+type FeedValue struct {
+	Type     FeedType
+	Value    common.Hash
+	Decimals uint16
+}
+
+// Verify returns an error if [v] is not a valid value of its type.
+func (v *FeedValue) Verify() error {
+	switch v.Type {
+	case FeedTypeInt256:
+		return nil
+	case FeedTypeBytes32, FeedTypeBool:
+		if v.Decimals != 0 {
+			return fmt.Errorf("%w: %s values have no decimals", ErrInvalidFeedValue, v.Type)
+		}
+		if v.Type == FeedTypeBool && v.Value.Big().Cmp(common.Big1) > 0 {
+			return fmt.Errorf("%w: bool value %s", ErrInvalidFeedValue, v.Value.Hex())
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s feeds do not publish typed values", ErrInvalidFeedValue, v.Type)
+	}
+}
+
+// Int returns the int256 held by [v].
+func (v *FeedValue) Int() *big.Int {
+	return math.S256(v.Value.Big())
+}
+
+// StoreFeedValue writes [value] as the latest value of [id] to [state] and records [timestamp] as the
+// time at which it was written. The price slot of [id] holds:
+// [0] set flag, [1] type, [2:4] decimals.
+func StoreFeedValue(state StateDB, id PriceFeedId, value *FeedValue, timestamp uint64) {
+	var header common.Hash
+	header[0] = 1
+	header[1] = byte(value.Type)
+	binary.BigEndian.PutUint16(header[2:4], value.Decimals)
+	state.SetState(PriceOracleAddress, common.Hash(id), header)
+	state.SetState(PriceOracleAddress, feedValueKey(id), value.Value)
+	setPriceUpdateTime(state, id, timestamp)
+}
+
+// ReadFeedValueFromState returns the latest value of the typed feed [id] in [state], or false if no
+// value has been written for it.
+func ReadFeedValueFromState(state StateDB, id PriceFeedId) (*FeedValue, bool) {
+	header := state.GetState(PriceOracleAddress, common.Hash(id))
+	if header[0] == 0 {
+		return nil, false
+	}
+	return &FeedValue{
+		Type:     FeedType(header[1]),
+		Value:    state.GetState(PriceOracleAddress, feedValueKey(id)),
+		Decimals: binary.BigEndian.Uint16(header[2:4]),
+	}, true
+}
+
+// WriteFeedValueToState writes [value] with [decimals] decimals, carried by the header of the block at
+// [timestamp], to the typed feed registered for [symbol]. Values for price feeds are rejected, their
+// prices are written by WritePriceToState.
+func WriteFeedValueToState(state StateDB, symbol string, value common.Hash, decimals uint16, timestamp uint64) error {
+	if !state.Exist(PriceOracleAddress) {
+		state.CreateAccount(PriceOracleAddress)
+	}
+
+	id, feedValue, err := CheckFeedValue(state, symbol, value, decimals)
+	if err != nil {
+		return err
+	}
+	StoreFeedValue(state, id, feedValue, timestamp)
+	return nil
+}
+
+// CheckFeedValue returns the id of the typed feed registered for [symbol] in [state] and the value
+// that [value] with [decimals] decimals is stored as, or an error if [symbol] is not registered,
+// is a price feed or [value] is not a valid value of the type of the feed.
+func CheckFeedValue(state StateDB, symbol string, value common.Hash, decimals uint16) (PriceFeedId, *FeedValue, error) {
+	id, ok := GetFeedIdBySymbol(state, symbol)
+	if !ok {
+		return id, nil, fmt.Errorf("Symbol id not currently supported to write. Key %s", symbol)
+	}
+	info, _ := GetFeedInfo(state, id)
+	if info.Type == FeedTypePrice {
+		return id, nil, fmt.Errorf("%w: %s is a price feed", ErrWrongFeedType, symbol)
+	}
+	feedValue := &FeedValue{Type: info.Type, Value: value, Decimals: decimals}
+	if err := feedValue.Verify(); err != nil {
+		return id, nil, fmt.Errorf("invalid value for %s: %w", symbol, err)
+	}
+	return id, feedValue, nil
+}
+
+// PackGetFeedValueInput packs [identifier] into the input data to the getter of values of [feedType].
+func PackGetFeedValueInput(feedType FeedType, identifier *PriceFeedId) ([]byte, error) {
+	var selector []byte
+	switch feedType {
+	case FeedTypeInt256:
+		selector = getIntSignature
+	case FeedTypeBytes32:
+		selector = getBytes32Signature
+	case FeedTypeBool:
+		selector = getBoolSignature
+	default:
+		return nil, fmt.Errorf("no getter for %s values", feedType)
+	}
+	return append(append([]byte{}, selector...), identifier.Bytes()...), nil
+}
+
+// UnpackGetIntOutput attempts to unpack the value and expo returned by the getInt function.
+func UnpackGetIntOutput(output []byte) (*big.Int, int32, error) {
+	if len(output) != 2*common.HashLength {
+		return nil, 0, fmt.Errorf("invalid output length for getInt: %d", len(output))
+	}
+	value := math.S256(new(big.Int).SetBytes(output[:common.HashLength]))
+	expo := math.S256(new(big.Int).SetBytes(output[common.HashLength:]))
+	if !expo.IsInt64() || expo.Int64() < math.MinInt32 || expo.Int64() > math.MaxInt32 {
+		return nil, 0, fmt.Errorf("expo %d does not fit in 32 bits", expo)
+	}
+	return value, int32(expo.Int64()), nil
+}
+
+// readFeedValue charges [cost] for reading [id] from [suppliedGas] and returns the latest value of [id].
+// It fails with UnknownFeed if [id] is not registered, with WrongFeedType if [id] does not publish values
+// of [feedType] and with StalePrice if no value has been written for it.
+func readFeedValue(stateDB StateDB, addr common.Address, id PriceFeedId, feedType FeedType, cost FunctionGasCost, suppliedGas uint64) (*FeedValue, uint64, error) {
+	remainingGas, err := chargeFeedAccess(stateDB, addr, id, cost, suppliedGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	if remainingGas, err = deductGas(remainingGas, GetFeedValueGasCost); err != nil {
+		return nil, 0, err
+	}
+	info, ok := GetFeedInfo(stateDB, id)
+	if !ok {
+		return nil, remainingGas, errUnknownFeed(id)
+	}
+	if info.Type != feedType {
+		return nil, remainingGas, errWrongFeedType(info)
+	}
+	value, ok := ReadFeedValueFromState(stateDB, id)
+	if !ok {
+		return nil, remainingGas, errStalePrice(id, GetPriceUpdateTime(stateDB, id))
+	}
+	return value, remainingGas, nil
+}
+
+// createGetFeedValue returns the execution function for the getter of values of [feedType] charging [cost].
+// int256 values are returned with their expo, bytes32 and bool values on their own.
+func createGetFeedValue(feedType FeedType, cost FunctionGasCost) RunStatefulPrecompileFunc {
+	return func(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if len(input) != GetFeedValueInputLen {
+			return nil, suppliedGas, fmt.Errorf("invalid input length for %s getter: %d", feedType, len(input))
+		}
+		identifier := BytesToPriceFeedId(input)
+
+		value, remainingGas, err := readFeedValue(accessibleState.GetStateDB(), addr, identifier, feedType, cost, suppliedGas)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if feedType != FeedTypeInt256 {
+			return value.Value.Bytes(), remainingGas, nil
+		}
+		return append(value.Value.Bytes(), math.U256Bytes(big.NewInt(-int64(value.Decimals)))...), remainingGas, nil
+	}
+}
@@ -0,0 +1,509 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gattaca-com/oracle-evm/vmerrs"
+)
+
+const (
+	// MaxPriceTriggerGasLimit is the largest amount of gas a trigger can prepay for its callback.
+	MaxPriceTriggerGasLimit = 1_000_000
+	// MaxPriceTriggerDataLen is the largest calldata, in bytes, a trigger can store for its callback.
+	MaxPriceTriggerDataLen = 1024
+	// MaxActivePriceTriggers bounds the number of triggers checked against the prices of each block.
+	MaxActivePriceTriggers = 256
+
+	// DefaultPriceTriggerGasPerBlock is the gas available to trigger callbacks in each block when the
+	// config does not specify it.
+	DefaultPriceTriggerGasPerBlock = 2_000_000
+
+	// The fixed part of the input to registerTrigger: feed id, direction, threshold, target, gas limit
+	// and the offset of the calldata, followed by the length of the calldata.
+	registerTriggerHeadLen = 6 * common.HashLength
+)
+
+// PriceTriggerDirection is the side of the threshold the price must reach for a trigger to fire.
+type PriceTriggerDirection uint8
+
+const (
+	// PriceTriggerAbove fires once the price is greater than or equal to the threshold.
+	PriceTriggerAbove PriceTriggerDirection = iota
+	// PriceTriggerBelow fires once the price is less than or equal to the threshold.
+	PriceTriggerBelow
+)
+
+// Valid returns true iff [d] is a known direction.
+func (d PriceTriggerDirection) Valid() bool {
+	return d == PriceTriggerAbove || d == PriceTriggerBelow
+}
+
+var (
+	_ StatefulPrecompileConfig = &PriceTriggerConfig{}
+	// Singleton StatefulPrecompiledContract for registering price triggers.
+	PriceTriggerPrecompile StatefulPrecompiledContract = createPriceTriggerPrecompile()
+
+	registerTriggerSignature = CalculateFunctionSelector("registerTrigger(uint256,uint8,int256,address,uint64,bytes)")
+	cancelTriggerSignature   = CalculateFunctionSelector("cancelTrigger(uint256)")
+	getTriggerSignature      = CalculateFunctionSelector("getTrigger(uint256)")
+
+	ErrUnknownTrigger       = errors.New("unknown price trigger")
+	ErrNotTriggerOwner      = errors.New("only the owner can cancel a price trigger")
+	ErrTooManyPriceTriggers = errors.New("too many active price triggers")
+
+	// Triggers are stored under [PriceTriggerAddress] in slots derived from the following prefixes.
+	nextTriggerIdKey         = crypto.Keccak256Hash([]byte("priceTrigger.nextId"))
+	activeTriggerCountKey    = crypto.Keccak256Hash([]byte("priceTrigger.activeCount"))
+	activeTriggerPrefix      = []byte("priceTrigger.active")
+	triggerFieldPrefix       = []byte("priceTrigger.trigger")
+	priceTriggerGetOutputLen = 7 * common.HashLength
+)
+
+// Indices of the storage slots of a trigger. The calldata is stored from [triggerDataIndex] onwards.
+const (
+	// [0] registered flag, [1] direction, [2:10] gas limit, [10:12] calldata length, [12:32] owner
+	triggerHeaderIndex = iota
+	triggerFeedIndex
+	triggerThresholdIndex
+	// [0:20] target, [24:32] position in the active list
+	triggerTargetIndex
+	triggerDataIndex
+)
+
+// PriceTriggerConfig implements the StatefulPrecompileConfig interface for the price trigger precompile,
+// which lets contracts register callbacks that are executed once a price feed crosses a threshold.
+type PriceTriggerConfig struct {
+	UpgradeableConfig
+
+	// MaxGasPerBlock bounds the gas prepaid by the triggers executed in a single block. Triggers that
+	// do not fit remain active and are executed in a later block. Defaults to [DefaultPriceTriggerGasPerBlock].
+	MaxGasPerBlock uint64 `json:"maxGasPerBlock,omitempty"`
+}
+
+// NewPriceTriggerConfig returns a config for a network upgrade at [blockTimestamp] that enables
+// price triggers with [maxGasPerBlock] available to their callbacks in each block.
+func NewPriceTriggerConfig(blockTimestamp *big.Int, maxGasPerBlock uint64) *PriceTriggerConfig {
+	return &PriceTriggerConfig{
+		UpgradeableConfig: UpgradeableConfig{BlockTimestamp: blockTimestamp},
+		MaxGasPerBlock:    maxGasPerBlock,
+	}
+}
+
+// NewDisablePriceTriggerConfig returns config for a network upgrade at [blockTimestamp]
+// that disables price triggers, dropping all registered triggers.
+func NewDisablePriceTriggerConfig(blockTimestamp *big.Int) *PriceTriggerConfig {
+	return &PriceTriggerConfig{
+		UpgradeableConfig: UpgradeableConfig{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Address returns the address of the price trigger contract.
+func (c *PriceTriggerConfig) Address() common.Address {
+	return PriceTriggerAddress
+}
+
+// Configure is a no-op: triggers are only registered by calls to the precompile.
+func (c *PriceTriggerConfig) Configure(state StateDB, blockNumber *big.Int) {}
+
+// Contract returns the singleton stateful precompiled contract to be used for price triggers.
+func (c *PriceTriggerConfig) Contract() StatefulPrecompiledContract {
+	return PriceTriggerPrecompile
+}
+
+// Verify returns an error if the gas available to triggers in each block cannot fit the largest trigger.
+func (c *PriceTriggerConfig) Verify() error {
+	if c.MaxGasPerBlock != 0 && c.MaxGasPerBlock < MaxPriceTriggerGasLimit {
+		return fmt.Errorf("price trigger maxGasPerBlock (%d) cannot be less than the max trigger gas limit (%d)", c.MaxGasPerBlock, MaxPriceTriggerGasLimit)
+	}
+	return nil
+}
+
+// Equal returns true if [s] is a [*PriceTriggerConfig] and it has been configured identical to [c].
+func (c *PriceTriggerConfig) Equal(s StatefulPrecompileConfig) bool {
+	// typecast before comparison
+	other, ok := (s).(*PriceTriggerConfig)
+	if !ok {
+		return false
+	}
+	return c.UpgradeableConfig.Equal(&other.UpgradeableConfig) && c.MaxGasPerBlock == other.MaxGasPerBlock
+}
+
+// GetMaxGasPerBlock returns the gas available to trigger callbacks in each block.
+func (c *PriceTriggerConfig) GetMaxGasPerBlock() uint64 {
+	if c.MaxGasPerBlock == 0 {
+		return DefaultPriceTriggerGasPerBlock
+	}
+	return c.MaxGasPerBlock
+}
+
+// PriceTrigger calls [Target] with [Data] from [Owner] once the price of [Feed] crosses [Threshold]
+// in [Direction]. The callback runs with [GasLimit] gas, which was paid for when it was registered.
+type PriceTrigger struct {
+	Id        uint64
+	Owner     common.Address
+	Feed      PriceFeedId
+	Direction PriceTriggerDirection
+	Threshold int64
+	Target    common.Address
+	GasLimit  uint64
+	Data      []byte
+}
+
+// Fires returns true if [price] satisfies the condition of [t].
+func (t *PriceTrigger) Fires(price int64) bool {
+	if t.Direction == PriceTriggerAbove {
+		return price >= t.Threshold
+	}
+	return price <= t.Threshold
+}
+
+// triggerFieldKey returns the storage slot holding the field at [index] of the trigger [id].
+func triggerFieldKey(id uint64, index uint64) common.Hash {
+	return crypto.Keccak256Hash(triggerFieldPrefix, common.BigToHash(new(big.Int).SetUint64(id)).Bytes(), common.BigToHash(new(big.Int).SetUint64(index)).Bytes())
+}
+
+// activeTriggerKey returns the storage slot holding the id of the trigger at [position] in the active list.
+func activeTriggerKey(position uint64) common.Hash {
+	return crypto.Keccak256Hash(activeTriggerPrefix, common.BigToHash(new(big.Int).SetUint64(position)).Bytes())
+}
+
+// getTriggerUint64 returns the integer stored at [key] under [PriceTriggerAddress].
+func getTriggerUint64(state StateDB, key common.Hash) uint64 {
+	return state.GetState(PriceTriggerAddress, key).Big().Uint64()
+}
+
+// setTriggerUint64 stores [value] at [key] under [PriceTriggerAddress].
+func setTriggerUint64(state StateDB, key common.Hash, value uint64) {
+	state.SetState(PriceTriggerAddress, key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+// GetActivePriceTriggerCount returns the number of triggers that have neither fired nor been cancelled.
+func GetActivePriceTriggerCount(state StateDB) uint64 {
+	return getTriggerUint64(state, activeTriggerCountKey)
+}
+
+// GetPriceTrigger returns the active trigger [id], or false if [id] is unknown, has fired or has been cancelled.
+func GetPriceTrigger(state StateDB, id uint64) (*PriceTrigger, bool) {
+	header := state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerHeaderIndex))
+	if header[0] == 0 {
+		return nil, false
+	}
+	target := state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerTargetIndex))
+	trigger := &PriceTrigger{
+		Id:        id,
+		Owner:     common.BytesToAddress(header[12:32]),
+		Feed:      PriceFeedId(state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerFeedIndex))),
+		Direction: PriceTriggerDirection(header[1]),
+		Threshold: math.S256(state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerThresholdIndex)).Big()).Int64(),
+		Target:    common.BytesToAddress(target[:common.AddressLength]),
+		GasLimit:  binary.BigEndian.Uint64(header[2:10]),
+	}
+	dataLen := int(binary.BigEndian.Uint16(header[10:12]))
+	trigger.Data = make([]byte, 0, dataLen)
+	for i := uint64(0); len(trigger.Data) < dataLen; i++ {
+		word := state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerDataIndex+i))
+		trigger.Data = append(trigger.Data, word[:minInt(common.HashLength, dataLen-len(trigger.Data))]...)
+	}
+	return trigger, true
+}
+
+// storePriceTrigger assigns the next id to [trigger], stores it and appends it to the active list.
+// assumes [trigger] has already been verified.
+func storePriceTrigger(state StateDB, trigger *PriceTrigger) {
+	trigger.Id = getTriggerUint64(state, nextTriggerIdKey)
+	setTriggerUint64(state, nextTriggerIdKey, trigger.Id+1)
+
+	var header common.Hash
+	header[0] = 1
+	header[1] = byte(trigger.Direction)
+	binary.BigEndian.PutUint64(header[2:10], trigger.GasLimit)
+	binary.BigEndian.PutUint16(header[10:12], uint16(len(trigger.Data)))
+	copy(header[12:32], trigger.Owner.Bytes())
+	state.SetState(PriceTriggerAddress, triggerFieldKey(trigger.Id, triggerHeaderIndex), header)
+	state.SetState(PriceTriggerAddress, triggerFieldKey(trigger.Id, triggerFeedIndex), common.Hash(trigger.Feed))
+	state.SetState(PriceTriggerAddress, triggerFieldKey(trigger.Id, triggerThresholdIndex), common.BytesToHash(math.U256Bytes(big.NewInt(trigger.Threshold))))
+	for i := 0; i*common.HashLength < len(trigger.Data); i++ {
+		chunk := trigger.Data[i*common.HashLength : minInt((i+1)*common.HashLength, len(trigger.Data))]
+		var word common.Hash
+		copy(word[:], chunk)
+		state.SetState(PriceTriggerAddress, triggerFieldKey(trigger.Id, triggerDataIndex+uint64(i)), word)
+	}
+
+	position := GetActivePriceTriggerCount(state)
+	setTriggerTarget(state, trigger.Id, trigger.Target, position)
+	setTriggerUint64(state, activeTriggerKey(position), trigger.Id)
+	setTriggerUint64(state, activeTriggerCountKey, position+1)
+}
+
+func setTriggerTarget(state StateDB, id uint64, target common.Address, position uint64) {
+	var word common.Hash
+	copy(word[:common.AddressLength], target.Bytes())
+	binary.BigEndian.PutUint64(word[24:32], position)
+	state.SetState(PriceTriggerAddress, triggerFieldKey(id, triggerTargetIndex), word)
+}
+
+// RemovePriceTrigger deletes the active trigger [id] and removes it from the active list, by moving the
+// last active trigger into its position.
+func RemovePriceTrigger(state StateDB, id uint64) {
+	trigger, ok := GetPriceTrigger(state, id)
+	if !ok {
+		return
+	}
+	targetWord := state.GetState(PriceTriggerAddress, triggerFieldKey(id, triggerTargetIndex))
+	position := binary.BigEndian.Uint64(targetWord[24:32])
+	last := GetActivePriceTriggerCount(state) - 1
+	if position != last {
+		lastId := getTriggerUint64(state, activeTriggerKey(last))
+		lastTarget := state.GetState(PriceTriggerAddress, triggerFieldKey(lastId, triggerTargetIndex))
+		setTriggerTarget(state, lastId, common.BytesToAddress(lastTarget[:common.AddressLength]), position)
+		setTriggerUint64(state, activeTriggerKey(position), lastId)
+	}
+	state.SetState(PriceTriggerAddress, activeTriggerKey(last), common.Hash{})
+	setTriggerUint64(state, activeTriggerCountKey, last)
+
+	for index := uint64(0); index < triggerDataIndex+triggerDataWords(trigger.Data); index++ {
+		state.SetState(PriceTriggerAddress, triggerFieldKey(id, index), common.Hash{})
+	}
+}
+
+// FiredPriceTriggers returns the active triggers whose condition is met by the latest prices in [state],
+// in the order of the active list.
+func FiredPriceTriggers(state StateDB) []*PriceTrigger {
+	var fired []*PriceTrigger
+	count := GetActivePriceTriggerCount(state)
+	for position := uint64(0); position < count; position++ {
+		trigger, ok := GetPriceTrigger(state, getTriggerUint64(state, activeTriggerKey(position)))
+		if !ok {
+			continue
+		}
+		if price, ok := ReadPriceFromState(state, trigger.Feed); ok && trigger.Fires(price.Price) {
+			fired = append(fired, trigger)
+		}
+	}
+	return fired
+}
+
+// PackRegisterTriggerInput packs [trigger] into the input data to the register trigger function.
+// The id and owner of [trigger] are ignored.
+func PackRegisterTriggerInput(trigger *PriceTrigger) []byte {
+	paddedLen := int(triggerDataWords(trigger.Data)) * common.HashLength
+	input := make([]byte, 0, selectorLen+registerTriggerHeadLen+common.HashLength+paddedLen)
+	input = append(input, registerTriggerSignature...)
+	input = append(input, common.Hash(trigger.Feed).Bytes()...)
+	input = append(input, common.BigToHash(big.NewInt(int64(trigger.Direction))).Bytes()...)
+	input = append(input, math.U256Bytes(big.NewInt(trigger.Threshold))...)
+	input = append(input, trigger.Target.Hash().Bytes()...)
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(trigger.GasLimit)).Bytes()...)
+	input = append(input, common.BigToHash(big.NewInt(registerTriggerHeadLen)).Bytes()...)
+	input = append(input, common.BigToHash(big.NewInt(int64(len(trigger.Data)))).Bytes()...)
+	input = append(input, common.RightPadBytes(trigger.Data, paddedLen)...)
+	return input
+}
+
+// UnpackRegisterTriggerInput attempts to unpack [input] into the arguments to the register trigger function.
+// assumes that [input] does not include selector (omits first 4 bytes in PackRegisterTriggerInput)
+func UnpackRegisterTriggerInput(input []byte) (*PriceTrigger, error) {
+	if len(input) < registerTriggerHeadLen+common.HashLength {
+		return nil, fmt.Errorf("invalid input length for registering price trigger: %d", len(input))
+	}
+	word := func(i int) *big.Int {
+		return new(big.Int).SetBytes(input[i*common.HashLength : (i+1)*common.HashLength])
+	}
+
+	direction := word(1)
+	if !direction.IsUint64() || !PriceTriggerDirection(direction.Uint64()).Valid() {
+		return nil, fmt.Errorf("invalid price trigger direction: %d", direction)
+	}
+	threshold := math.S256(word(2))
+	if !threshold.IsInt64() {
+		return nil, fmt.Errorf("price trigger threshold %d does not fit in 64 bits", threshold)
+	}
+	target := word(3)
+	if target.BitLen() > 8*common.AddressLength {
+		return nil, fmt.Errorf("invalid price trigger target: %#x", target)
+	}
+	gasLimit := word(4)
+	if !gasLimit.IsUint64() || gasLimit.Uint64() == 0 || gasLimit.Uint64() > MaxPriceTriggerGasLimit {
+		return nil, fmt.Errorf("price trigger gas limit %d must be between 1 and %d", gasLimit, MaxPriceTriggerGasLimit)
+	}
+
+	offset := word(5)
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(input)-common.HashLength) {
+		return nil, fmt.Errorf("invalid price trigger calldata offset: %d", offset)
+	}
+	dataStart := offset.Uint64() + common.HashLength
+	dataLen := new(big.Int).SetBytes(input[offset.Uint64():dataStart])
+	if !dataLen.IsUint64() || dataLen.Uint64() > MaxPriceTriggerDataLen {
+		return nil, fmt.Errorf("price trigger calldata cannot exceed %d bytes", MaxPriceTriggerDataLen)
+	}
+	if dataStart+dataLen.Uint64() > uint64(len(input)) {
+		return nil, fmt.Errorf("invalid input length for price trigger calldata of %d bytes: %d", dataLen, len(input))
+	}
+
+	return &PriceTrigger{
+		Feed:      BytesToPriceFeedId(input[:common.HashLength]),
+		Direction: PriceTriggerDirection(direction.Uint64()),
+		Threshold: threshold.Int64(),
+		Target:    common.BigToAddress(target),
+		GasLimit:  gasLimit.Uint64(),
+		Data:      common.CopyBytes(input[dataStart : dataStart+dataLen.Uint64()]),
+	}, nil
+}
+
+// PackCancelTriggerInput packs [id] into the input data to the cancel trigger function.
+func PackCancelTriggerInput(id uint64) []byte {
+	input := make([]byte, 0, selectorLen+common.HashLength)
+	input = append(input, cancelTriggerSignature...)
+	return append(input, common.BigToHash(new(big.Int).SetUint64(id)).Bytes()...)
+}
+
+// PackGetTriggerInput packs [id] into the input data to the get trigger function.
+func PackGetTriggerInput(id uint64) []byte {
+	input := make([]byte, 0, selectorLen+common.HashLength)
+	input = append(input, getTriggerSignature...)
+	return append(input, common.BigToHash(new(big.Int).SetUint64(id)).Bytes()...)
+}
+
+// unpackTriggerIdInput attempts to unpack [input] into the id argument of the cancel and get trigger functions.
+func unpackTriggerIdInput(input []byte) (uint64, error) {
+	if len(input) != common.HashLength {
+		return 0, fmt.Errorf("invalid input length for price trigger id: %d", len(input))
+	}
+	id := new(big.Int).SetBytes(input)
+	if !id.IsUint64() {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownTrigger, id)
+	}
+	return id.Uint64(), nil
+}
+
+// registerTrigger stores a trigger owned by the caller and returns its id. The caller prepays the gas limit
+// of the callback on top of the cost of storing the trigger, and the gas is not refunded if the trigger is
+// cancelled or its callback uses less.
+func registerTrigger(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, RegisterTriggerGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	trigger, err := UnpackRegisterTriggerInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if remainingGas, err = deductGas(remainingGas, triggerDataWords(trigger.Data)*writeGasCostPerSlot+trigger.GasLimit); err != nil {
+		return nil, 0, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	info, ok := GetFeedInfo(stateDB, trigger.Feed)
+	if !ok {
+		return nil, remainingGas, errUnknownFeed(trigger.Feed)
+	}
+	if info.Type != FeedTypePrice {
+		return nil, remainingGas, errWrongFeedType(info)
+	}
+	if GetActivePriceTriggerCount(stateDB) >= MaxActivePriceTriggers {
+		return nil, remainingGas, ErrTooManyPriceTriggers
+	}
+
+	trigger.Owner = caller
+	storePriceTrigger(stateDB, trigger)
+	return common.BigToHash(new(big.Int).SetUint64(trigger.Id)).Bytes(), remainingGas, nil
+}
+
+// cancelTrigger removes an active trigger owned by the caller.
+func cancelTrigger(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, CancelTriggerGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+
+	id, err := unpackTriggerIdInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	trigger, ok := GetPriceTrigger(stateDB, id)
+	if !ok {
+		return nil, remainingGas, fmt.Errorf("%w: %d", ErrUnknownTrigger, id)
+	}
+	if trigger.Owner != caller {
+		return nil, remainingGas, fmt.Errorf("%w: %s", ErrNotTriggerOwner, caller)
+	}
+	if remainingGas, err = deductGas(remainingGas, triggerDataWords(trigger.Data)*writeGasCostPerSlot); err != nil {
+		return nil, 0, err
+	}
+	RemovePriceTrigger(stateDB, id)
+	// Return an empty output and the remaining gas
+	return []byte{}, remainingGas, nil
+}
+
+// getTrigger returns the owner, feed, direction, threshold, target, gas limit and active flag of a trigger.
+// Triggers that have fired or been cancelled are returned as inactive with all other fields zeroed.
+func getTrigger(accessibleState PrecompileAccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = deductGas(suppliedGas, GetTriggerGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	id, err := unpackTriggerIdInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	trigger, ok := GetPriceTrigger(accessibleState.GetStateDB(), id)
+	if !ok {
+		return make([]byte, priceTriggerGetOutputLen), remainingGas, nil
+	}
+	output := make([]byte, 0, priceTriggerGetOutputLen)
+	output = append(output, trigger.Owner.Hash().Bytes()...)
+	output = append(output, common.Hash(trigger.Feed).Bytes()...)
+	output = append(output, common.BigToHash(big.NewInt(int64(trigger.Direction))).Bytes()...)
+	output = append(output, math.U256Bytes(big.NewInt(trigger.Threshold))...)
+	output = append(output, trigger.Target.Hash().Bytes()...)
+	output = append(output, common.BigToHash(new(big.Int).SetUint64(trigger.GasLimit)).Bytes()...)
+	output = append(output, common.BigToHash(common.Big1).Bytes()...)
+	return output, remainingGas, nil
+}
+
+// createPriceTriggerPrecompile returns a StatefulPrecompiledContract with functions to register, cancel
+// and read price triggers.
+func createPriceTriggerPrecompile() StatefulPrecompiledContract {
+	functions := []*statefulPrecompileFunction{
+		newStatefulPrecompileFunction(registerTriggerSignature, registerTrigger),
+		newStatefulPrecompileFunction(cancelTriggerSignature, cancelTrigger),
+		newStatefulPrecompileFunction(getTriggerSignature, getTrigger),
+	}
+
+	// Construct the contract with no fallback function.
+	return newStatefulPrecompileWithFunctionSelectors(nil, functions)
+}
+
+// triggerDataWords returns the number of storage slots holding [data].
+func triggerDataWords(data []byte) uint64 {
+	return uint64(len(data)+common.HashLength-1) / common.HashLength
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,20 @@
+pragma solidity >=0.8.4;
+
+interface NativePriceTriggerInterface {
+
+    // Reverted when registering a trigger on a feed that is not registered with the price oracle.
+    error UnknownFeed(uint256 identifier);
+    // Reverted when registering a trigger on a typed feed, which has no price.
+    error WrongFeedType(uint256 identifier, uint8 feedType);
+
+    // Registers a call to [target] with [data] that is executed at the start of the first block in which the
+    // price of [identifier] is >= [threshold] (direction 0) or <= [threshold] (direction 1).
+    // The gas limit of the call is charged on registration. Returns the id of the trigger.
+    function registerTrigger(uint256 identifier, uint8 direction, int256 threshold, address target, uint64 gasLimit, bytes calldata data) external returns (uint256);
+
+    // Cancels a trigger that has not fired yet. Only the owner of the trigger can cancel it.
+    function cancelTrigger(uint256 id) external;
+
+    // Returns the trigger with [id]. [active] is false once the trigger has fired or has been cancelled.
+    function getTrigger(uint256 id) external view returns (address owner, uint256 identifier, uint8 direction, int256 threshold, address target, uint64 gasLimit, bool active);
+}
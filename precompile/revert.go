@@ -0,0 +1,30 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+// RevertError is an error of a stateful precompile that reverts the call with the ABI encoding
+// of a Solidity custom error as revert data, so that callers can handle it with try/catch and
+// off-chain tools can decode it. Unlike other errors, it does not consume the remaining gas.
+type RevertError struct {
+	err  error
+	data []byte
+}
+
+// newRevertError returns a RevertError for [err] whose revert data is the selector of the
+// custom error [signature] followed by [args], each of which must be a 32 byte ABI word.
+func newRevertError(err error, signature string, args ...[]byte) *RevertError {
+	data := CalculateFunctionSelector(signature)
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+	return &RevertError{err: err, data: data}
+}
+
+func (e *RevertError) Error() string { return e.err.Error() }
+
+// Unwrap returns the underlying error, so that RevertErrors can be matched with errors.Is.
+func (e *RevertError) Unwrap() error { return e.err }
+
+// Data returns the ABI encoded custom error returned to the caller.
+func (e *RevertError) Data() []byte { return e.data }
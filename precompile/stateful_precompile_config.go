@@ -7,20 +7,23 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-
-	"github.com/gattaca-com/oracle-evm/utils"
 )
 
 // StatefulPrecompileConfig defines the interface for a stateful precompile to
 type StatefulPrecompileConfig interface {
 	// Address returns the address where the stateful precompile is accessible.
 	Address() common.Address
-	// Timestamp returns the timestamp at which this stateful precompile should be enabled.
-	// 1) 0 indicates that the precompile should be enabled from genesis.
-	// 2) n indicates that the precompile should be enabled in the first block with timestamp >= [n].
-	// 3) nil indicates that the precompile is never enabled.
+	// Timestamp returns the timestamp at which this stateful precompile config takes effect.
+	// 1) 0 indicates that the config takes effect from genesis.
+	// 2) n indicates that the config takes effect in the first block with timestamp >= [n].
+	// 3) nil indicates that the config never takes effect.
 	Timestamp() *big.Int
-	// Configure is called on the first block where the stateful precompile should be enabled.
+	// IsDisabled returns true if this config deactivates the precompile rather than enabling or
+	// reconfiguring it.
+	IsDisabled() bool
+	// Equal returns true if [other] is the same config as this one.
+	Equal(other StatefulPrecompileConfig) bool
+	// Configure is called on the first block where the stateful precompile should be enabled or reconfigured.
 	// This allows the stateful precompile to configure its own state via [StateDB] as necessary.
 	// This function must be deterministic since it will impact the EVM state. If a change to the
 	// config causes a change to the state modifications made in Configure, then it cannot be safely
@@ -28,31 +31,42 @@ type StatefulPrecompileConfig interface {
 	//
 	// Configure is called on the first block where the stateful precompile should be enabled. This
 	// provides the config the ability to set its initial state and should only modify the state within
-	// its own address space.
-	Configure(StateDB)
+	// its own address space. [blockNumber] is the number of that block.
+	Configure(state StateDB, blockNumber *big.Int)
 	// Contract returns a thread-safe singleton that can be used as the StatefulPrecompiledContract when
 	// this config is enabled.
 	Contract() StatefulPrecompiledContract
+	// Verify returns an error if the config is invalid.
+	Verify() error
+}
+
+// ConfigurableStateDB is the interface required to enable, reconfigure or disable stateful precompiles
+// between blocks.
+type ConfigurableStateDB interface {
+	StateDB
+	Suicide(common.Address) bool
+	Finalise(deleteEmptyObjects bool)
 }
 
-// CheckConfigure checks if [config] is activated by the transition from block at [parentTimestamp] to [currentTimestamp].
-// If it does, then it calls Configure on [config] to make the necessary state update to enable the StatefulPrecompile.
-// Note: this function is called within genesis to configure the starting state if it [config] specifies that it should be
+// Configure enables [config] by marking its address as a contract and calling Configure on it.
+// Note: this function is called within genesis to configure the starting state if [config] specifies that it should be
 // configured at genesis, or happens during block processing to update the state before processing the given block.
-// TODO: add ability to call Configure at different timestamps, so that developers can easily re-configure by updating the
-// stateful precompile config.
-// Assumes that [config] is non-nil.
-func CheckConfigure(parentTimestamp *big.Int, currentTimestamp *big.Int, config StatefulPrecompileConfig, state StateDB) {
-	forkTimestamp := config.Timestamp()
-	// If the network upgrade goes into effect within this transition, configure the stateful precompile
-	if utils.IsForkTransition(forkTimestamp, parentTimestamp, currentTimestamp) {
-		// Set the nonce of the precompile's address (as is done when a contract is created) to ensure
-		// that it is marked as non-empty and will not be cleaned up when the statedb is finalized.
-		state.SetNonce(config.Address(), 1)
-		// Set the code of the precompile's address to a non-zero length byte slice to ensure that the precompile
-		// can be called from within Solidity contracts. Solidity adds a check before invoking a contract to ensure
-		// that it does not attempt to invoke a non-existent contract.
-		state.SetCode(config.Address(), []byte{0x1})
-		config.Configure(state)
-	}
+// Assumes that [config] is non-nil and not disabled.
+func Configure(config StatefulPrecompileConfig, state StateDB, blockNumber *big.Int) {
+	// Set the nonce of the precompile's address (as is done when a contract is created) to ensure
+	// that it is marked as non-empty and will not be cleaned up when the statedb is finalized.
+	state.SetNonce(config.Address(), 1)
+	// Set the code of the precompile's address to a non-zero length byte slice to ensure that the precompile
+	// can be called from within Solidity contracts. Solidity adds a check before invoking a contract to ensure
+	// that it does not attempt to invoke a non-existent contract.
+	state.SetCode(config.Address(), []byte{0x1})
+	config.Configure(state, blockNumber)
+}
+
+// Disable removes the account of the precompile at [address] along with all of its storage, so that
+// a later re-enable starts from a clean state.
+func Disable(address common.Address, state ConfigurableStateDB) {
+	state.Suicide(address)
+	// Finalise immediately, so that the account is deleted before any transactions in the block execute.
+	state.Finalise(true)
 }
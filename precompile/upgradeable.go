@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package precompile
+
+import (
+	"math/big"
+
+	"github.com/gattaca-com/oracle-evm/utils"
+)
+
+// UpgradeableConfig contains the timestamp at which a stateful precompile config takes effect
+// along with a boolean [Disable]. If [Disable] is set, the upgrade deactivates the precompile
+// and clears its storage instead of configuring it.
+type UpgradeableConfig struct {
+	BlockTimestamp *big.Int `json:"blockTimestamp"`
+	Disable        bool     `json:"disable,omitempty"`
+}
+
+// Timestamp returns the timestamp at which the upgrade takes effect.
+func (c *UpgradeableConfig) Timestamp() *big.Int {
+	return c.BlockTimestamp
+}
+
+// IsDisabled returns true if the upgrade deactivates the precompile.
+func (c *UpgradeableConfig) IsDisabled() bool {
+	return c.Disable
+}
+
+// Equal returns true if [other] takes effect at the same timestamp and has the same [Disable] setting.
+func (c *UpgradeableConfig) Equal(other *UpgradeableConfig) bool {
+	if other == nil {
+		return false
+	}
+	return c.Disable == other.Disable && utils.BigNumEqual(c.BlockTimestamp, other.BlockTimestamp)
+}